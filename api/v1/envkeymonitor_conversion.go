@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// V2SpecAnnotation holds the v2 spec of an object served as v1, so that fields which
+// cannot be expressed in v1 survive a round trip through v1
+const V2SpecAnnotation = "config.core.nvsh-ram.io/v2-spec"
+
+// V2StatusAnnotation holds the v2 status of an object served as v1, so that status fields which
+// cannot be expressed in v1 survive a round trip through v1
+const V2StatusAnnotation = "config.core.nvsh-ram.io/v2-status"
+
+// ConvertTo converts this EnvKeyMonitor (v1) to the Hub version (v2).
+func (src *EnvKeyMonitor) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*configv2.EnvKeyMonitor)
+	if !ok {
+		return fmt.Errorf("expected a v2 EnvKeyMonitor object but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+
+	// Restore v2 only fields saved during a previous down conversion
+	var saved configv2.EnvKeyMonitorSpec
+	if err := restore(dst, V2SpecAnnotation, &saved); err != nil {
+		return err
+	}
+	if err := restore(dst, V2StatusAnnotation, &dst.Status); err != nil {
+		return err
+	}
+	dst.Status.Conditions = src.Status.Conditions
+
+	// Several v2 rules can share a name, e.g. an Exact and a Prefix rule, and are all restored
+	// for their name
+	savedRules := make(map[string][]configv2.KeyRule, len(saved.Rules))
+	for _, rule := range saved.Rules {
+		savedRules[rule.Name] = append(savedRules[rule.Name], rule)
+	}
+
+	// Keys edited through v1 take precedence, anything else is taken from the saved spec
+	dst.Spec = saved
+	dst.Spec.Policy = src.Spec.Policy
+	dst.Spec.Rules = nil
+	restored := map[configv2.KeyRule]bool{}
+	for _, key := range src.Spec.Keys {
+		rules, ok := savedRules[key]
+		if !ok {
+			rules = []configv2.KeyRule{{Name: key, Match: configv2.MatchExact}}
+		}
+		for _, rule := range rules {
+			if id := identity(rule); !restored[id] {
+				restored[id] = true
+				dst.Spec.Rules = append(dst.Spec.Rules, rule)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v2) to this EnvKeyMonitor (v1).
+func (dst *EnvKeyMonitor) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*configv2.EnvKeyMonitor)
+	if !ok {
+		return fmt.Errorf("expected a v2 EnvKeyMonitor object but got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Status.Conditions = src.Status.Conditions
+
+	// Keys are required in v1, objects only monitoring keys of v2 fields such as keySetRefs or
+	// presets have an empty list
+	dst.Spec.Policy = src.Spec.Policy
+	dst.Spec.Keys = []string{}
+	for _, rule := range src.Spec.Rules {
+		if !slices.Contains(dst.Spec.Keys, rule.Name) {
+			dst.Spec.Keys = append(dst.Spec.Keys, rule.Name)
+		}
+	}
+
+	// Save the v2 spec and status so they can be restored when converting back
+	status := src.Status.DeepCopy()
+	status.Conditions = nil
+	if err := save(dst, V2SpecAnnotation, src.Spec); err != nil {
+		return err
+	}
+	if err := save(dst, V2StatusAnnotation, status); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Write a value to an annotation of the object as JSON
+func save(obj *EnvKeyMonitor, annotation string, value any) error {
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to write annotation %s: %v", annotation, err)
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Annotations[annotation] = string(raw)
+	return nil
+}
+
+// Read a value written by save from an annotation of the object and remove the annotation
+func restore(obj *configv2.EnvKeyMonitor, annotation string, value any) error {
+
+	raw, ok := obj.Annotations[annotation]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(raw), value); err != nil {
+		return fmt.Errorf("failed to read annotation %s: %v", annotation, err)
+	}
+	delete(obj.Annotations, annotation)
+	if len(obj.Annotations) == 0 {
+		obj.Annotations = nil
+	}
+	return nil
+}
+
+// Get the identity of a rule, rules with the same name, match type and value regex are equal
+func identity(rule configv2.KeyRule) configv2.KeyRule {
+
+	if rule.Match == "" {
+		rule.Match = configv2.MatchExact
+	}
+	return configv2.KeyRule{Name: rule.Name, Match: rule.Match, ValueRegex: rule.ValueRegex}
+}
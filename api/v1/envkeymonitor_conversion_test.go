@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("EnvKeyMonitor Conversion", func() {
+
+	It("Should convert v1 to v2 and back without losing fields", func() {
+		By("converting a v1 object to v2")
+		src := &EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec:       EnvKeyMonitorSpec{Keys: []string{"API_KEY", "DB_PASSWORD"}, Policy: "STRICT"},
+		}
+		hub := &configv2.EnvKeyMonitor{}
+		Expect(src.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.Policy).To(Equal("STRICT"))
+		Expect(hub.Spec.Rules).To(Equal([]configv2.KeyRule{
+			{Name: "API_KEY", Match: configv2.MatchExact},
+			{Name: "DB_PASSWORD", Match: configv2.MatchExact},
+		}))
+
+		By("converting the v2 object back to v1")
+		dst := &EnvKeyMonitor{}
+		Expect(dst.ConvertFrom(hub)).To(Succeed())
+		Expect(dst.Spec).To(Equal(src.Spec))
+		Expect(dst.Name).To(Equal("monitor"))
+	})
+
+	It("Should convert v2 to v1 and back without losing fields", func() {
+		By("converting a v2 object with structured rules to v1")
+		hub := &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules: []configv2.KeyRule{
+					{Name: "AWS_", Match: configv2.MatchPrefix, Severity: configv2.SeverityHigh, Message: "use a Secret"},
+					{Name: "API_KEY", Match: configv2.MatchExact, DocsURL: "https://example.com"},
+				},
+				Policy: "PERMISSIVE",
+			},
+		}
+		spoke := &EnvKeyMonitor{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		Expect(spoke.Spec.Keys).To(Equal([]string{"AWS_", "API_KEY"}))
+		Expect(spoke.Annotations).To(HaveKey(V2SpecAnnotation))
+
+		By("converting the v1 object back to v2")
+		restored := &configv2.EnvKeyMonitor{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Spec).To(Equal(hub.Spec))
+		Expect(restored.Annotations).NotTo(HaveKey(V2SpecAnnotation))
+	})
+
+	It("Should keep keys edited through v1", func() {
+		hub := &configv2.EnvKeyMonitor{
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules: []configv2.KeyRule{
+					{Name: "AWS_", Match: configv2.MatchPrefix},
+					{Name: "API_KEY", Match: configv2.MatchExact},
+				},
+			},
+		}
+		spoke := &EnvKeyMonitor{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+
+		By("removing a key and adding another one through v1")
+		spoke.Spec.Keys = []string{"AWS_", "TOKEN"}
+		restored := &configv2.EnvKeyMonitor{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Spec.Rules).To(Equal([]configv2.KeyRule{
+			{Name: "AWS_", Match: configv2.MatchPrefix},
+			{Name: "TOKEN", Match: configv2.MatchExact},
+		}))
+	})
+
+	It("Should round-trip status, rules sharing a name and objects without rules through v1", func() {
+		hub := &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules: []configv2.KeyRule{
+					{Name: "AWS_", Match: configv2.MatchExact},
+					{Name: "AWS_", Match: configv2.MatchPrefix},
+					{Name: "TOKEN", Match: configv2.MatchContains, ValueRegex: "^ghp_"},
+					{Name: "TOKEN", Match: configv2.MatchContains, ValueRegex: "^glpat-"},
+				},
+			},
+			Status: configv2.EnvKeyMonitorStatus{
+				Conditions:        []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Reconciled"}},
+				SharedKeys:        []configv2.SharedKey{{Name: "AWS_", Monitors: []string{"other"}}},
+				Violations:        []configv2.ViolationRecord{{ConfigMap: "app", Key: "AWS_REGION", Rule: "AWS_"}},
+				EffectiveKeyCount: 4,
+			},
+		}
+		spoke := &EnvKeyMonitor{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		Expect(spoke.Spec.Keys).To(Equal([]string{"AWS_", "TOKEN"}))
+		Expect(spoke.Annotations).To(HaveKey(V2StatusAnnotation))
+
+		restored := &configv2.EnvKeyMonitor{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Spec).To(Equal(hub.Spec))
+		Expect(restored.Status).To(Equal(hub.Status))
+		Expect(restored.Annotations).To(BeNil())
+
+		By("converting an object only referencing EnvKeySets")
+		hub.Spec.Rules = nil
+		hub.Spec.KeySetRefs = []configv2.KeySetReference{{Name: "common"}}
+		spoke = &EnvKeyMonitor{}
+		Expect(spoke.ConvertFrom(hub)).To(Succeed())
+		Expect(spoke.Spec.Keys).To(BeEmpty())
+		Expect(spoke.Spec.Keys).NotTo(BeNil())
+
+		restored = &configv2.EnvKeyMonitor{}
+		Expect(spoke.ConvertTo(restored)).To(Succeed())
+		Expect(restored.Spec).To(Equal(hub.Spec))
+	})
+})
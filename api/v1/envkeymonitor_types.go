@@ -30,8 +30,8 @@ type EnvKeyMonitorSpec struct {
 	// The following markers will use OpenAPI v3 schema to validate the value
 	// More info: https://book.kubebuilder.io/reference/markers/crd-validation.html
 
-	// keys is a list of all environmental variable keys that need to be monitored
-	// +kubebuilder:validation:MinItems=1
+	// keys is a list of all environmental variable keys that need to be monitored. It is empty for
+	// objects only monitoring keys through fields of v2, e.g. keySetRefs or presets
 	// +kubebuilder:validation:MaxItems=25
 	// +kubebuilder:validation:Required
 	Keys []string `json:"keys"`
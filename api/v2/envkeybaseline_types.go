@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KeySource describes where an observed key was found
+// +kubebuilder:validation:Enum=ConfigMap;Workload
+type KeySource string
+
+const (
+	// KeySourceConfigMap is a key of a ConfigMap in the namespace
+	KeySourceConfigMap KeySource = "ConfigMap"
+	// KeySourceWorkload is the name of an environment variable set by a container of a Pod in the namespace
+	KeySourceWorkload KeySource = "Workload"
+)
+
+// EnvKeyBaselineSpec defines the desired state of EnvKeyBaseline
+type EnvKeyBaselineSpec struct {
+	// duration is the period keys are inventoried for, starting at the creation of the EnvKeyBaseline.
+	// Keys seen at any time during the period are part of the baseline
+	// +kubebuilder:default:="168h"
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// interval is the time between two inventories of the namespace
+	// +kubebuilder:default:="10m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// listType is the list type of the proposed EnvKeyMonitor.
+	// Valid values are:
+	// - "Denylist" (default): the presets matching suspicious keys are proposed
+	// - "Allowlist": the keys of ConfigMaps that are not suspicious are proposed as approved keys
+	// +kubebuilder:default:=Denylist
+	// +optional
+	ListType ListType `json:"listType,omitempty"`
+}
+
+// ObservedKey is a key seen while learning
+type ObservedKey struct {
+	// name is the key or environment variable name
+	Name string `json:"name"`
+
+	// sources lists where the key was found
+	// +listType=set
+	Sources []KeySource `json:"sources"`
+
+	// preset is the preset whose rules match the key, set for suspicious keys
+	// +optional
+	Preset Preset `json:"preset,omitempty"`
+
+	// firstSeen is the time of the first inventory finding the key
+	FirstSeen metav1.Time `json:"firstSeen"`
+
+	// lastSeen is the time of the last inventory finding the key
+	LastSeen metav1.Time `json:"lastSeen"`
+}
+
+// EnvKeyBaselineStatus defines the observed state of EnvKeyBaseline.
+type EnvKeyBaselineStatus struct {
+	// conditions represent the current state of the EnvKeyBaseline resource.
+	// The "Learning" condition is True until the duration has passed
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// keys are the keys seen while learning, ordered by name
+	// +kubebuilder:validation:MaxItems=1000
+	// +optional
+	Keys []ObservedKey `json:"keys,omitempty"`
+
+	// keyCount is the number of keys seen while learning
+	// +optional
+	KeyCount int32 `json:"keyCount,omitempty"`
+
+	// suspiciousKeyCount is the number of keys matched by the rules of a preset
+	// +optional
+	SuspiciousKeyCount int32 `json:"suspiciousKeyCount,omitempty"`
+
+	// lastInventoryTime is the time the namespace was last inventoried
+	// +optional
+	LastInventoryTime *metav1.Time `json:"lastInventoryTime,omitempty"`
+
+	// completionTime is the time learning completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// proposal is the spec of an EnvKeyMonitor proposed from the keys seen, for a human to review
+	// and accept. It is proposed in Shadow mode, so accepting it does not deny any ConfigMap
+	// +optional
+	Proposal *EnvKeyMonitorSpec `json:"proposal,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:resource:shortName=ekb
+// +kubebuilder:printcolumn:name="Keys",type=integer,JSONPath=`.status.keyCount`
+// +kubebuilder:printcolumn:name="Suspicious",type=integer,JSONPath=`.status.suspiciousKeyCount`
+// +kubebuilder:printcolumn:name="Learning",type=string,JSONPath=`.status.conditions[?(@.type=="Learning")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// EnvKeyBaseline is the Schema for the envkeybaselines API. While learning, the controller
+// inventories the ConfigMap keys and workload environment variable names of its namespace
+// and proposes an EnvKeyMonitor
+type EnvKeyBaseline struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of EnvKeyBaseline
+	// +required
+	Spec EnvKeyBaselineSpec `json:"spec"`
+
+	// status defines the observed state of EnvKeyBaseline
+	// +optional
+	Status EnvKeyBaselineStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnvKeyBaselineList contains a list of EnvKeyBaseline
+type EnvKeyBaselineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []EnvKeyBaseline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EnvKeyBaseline{}, &EnvKeyBaselineList{})
+}
@@ -0,0 +1,725 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MatchType describes how the name of a rule is compared against ConfigMap keys
+// +kubebuilder:validation:Enum=Exact;Prefix;Suffix;Contains;Regex
+type MatchType string
+
+const (
+	// MatchExact matches keys equal to the rule name
+	MatchExact MatchType = "Exact"
+	// MatchPrefix matches keys starting with the rule name
+	MatchPrefix MatchType = "Prefix"
+	// MatchSuffix matches keys ending with the rule name
+	MatchSuffix MatchType = "Suffix"
+	// MatchContains matches keys containing the rule name
+	MatchContains MatchType = "Contains"
+	// MatchRegex matches keys against the rule name as a regular expression
+	MatchRegex MatchType = "Regex"
+)
+
+// Severity describes how serious a violation of a rule is
+// +kubebuilder:validation:Enum=low;medium;high;critical
+type Severity string
+
+// Severities in ascending order
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// SeverityAction describes what happens to a ConfigMap violating a rule of a given severity
+// +kubebuilder:validation:Enum=Deny;Warn;Audit
+type SeverityAction string
+
+const (
+	// SeverityActionDeny denies the ConfigMap, as a STRICT policy does
+	SeverityActionDeny SeverityAction = "Deny"
+	// SeverityActionWarn admits the ConfigMap with a warning, as a PERMISSIVE policy does
+	SeverityActionWarn SeverityAction = "Warn"
+	// SeverityActionAudit admits the ConfigMap and only records the violation in events and metrics
+	SeverityActionAudit SeverityAction = "Audit"
+)
+
+// SeverityPolicy maps a severity to the action taken when a rule of that severity is violated
+type SeverityPolicy struct {
+	// severity of the rules the action applies to
+	// +kubebuilder:validation:Required
+	Severity Severity `json:"severity"`
+
+	// action taken when a rule of the severity is violated
+	// +kubebuilder:validation:Required
+	Action SeverityAction `json:"action"`
+}
+
+// BypassServiceAccount references a ServiceAccount exempted from denials
+type BypassServiceAccount struct {
+	// name of the ServiceAccount
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// namespace of the ServiceAccount, defaults to the namespace of the EnvKeyMonitor
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Bypass lists the subjects whose requests are admitted although they would be denied
+type Bypass struct {
+	// users are matched against the username of the request
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Users []string `json:"users,omitempty"`
+
+	// groups are matched against the groups of the requesting user
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// serviceAccounts are matched against the username of requests made by ServiceAccounts
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	ServiceAccounts []BypassServiceAccount `json:"serviceAccounts,omitempty"`
+}
+
+// EnforceOn describes which keys of an updated ConfigMap are enforced
+// +kubebuilder:validation:Enum=AddedKeys;AllKeys
+type EnforceOn string
+
+const (
+	// EnforceOnAddedKeys only enforces keys that were added or modified by the update
+	EnforceOnAddedKeys EnforceOn = "AddedKeys"
+	// EnforceOnAllKeys enforces all keys, unless the update reduces the number of violations
+	EnforceOnAllKeys EnforceOn = "AllKeys"
+)
+
+// Mode describes how the decisions of an EnvKeyMonitor are applied
+// +kubebuilder:validation:Enum=Enforce;Audit;Shadow
+type Mode string
+
+const (
+	// ModeEnforce denies or warns as described by the policy
+	ModeEnforce Mode = "Enforce"
+	// ModeAudit admits every ConfigMap and only records violations in events and metrics
+	ModeAudit Mode = "Audit"
+	// ModeShadow admits every ConfigMap and records what would have been denied in events,
+	// metrics and status, warnings are returned as in Enforce mode
+	ModeShadow Mode = "Shadow"
+)
+
+// ListType describes whether the rules of an EnvKeyMonitor list forbidden or approved keys
+// +kubebuilder:validation:Enum=Denylist;Allowlist
+type ListType string
+
+const (
+	// ListTypeDenylist forbids keys matched by a rule
+	ListTypeDenylist ListType = "Denylist"
+	// ListTypeAllowlist forbids keys not matched by any rule
+	ListTypeAllowlist ListType = "Allowlist"
+)
+
+// KeyNamePolicy is a naming convention every ConfigMap key has to follow
+// +kubebuilder:validation:XValidation:rule="has(self.pattern) || has(self.maxLength)",message="at least one of pattern or maxLength is required"
+type KeyNamePolicy struct {
+	// pattern is a regular expression every key has to match, e.g. ^[A-Z][A-Z0-9_]*$
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+
+	// maxLength is the maximum number of characters of a key
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxLength *int32 `json:"maxLength,omitempty"`
+}
+
+// RequiredKeys lists keys the selected ConfigMaps have to contain
+type RequiredKeys struct {
+	// configMapSelector selects the ConfigMaps of the namespace that have to contain the keys,
+	// an empty selector selects every ConfigMap
+	// +kubebuilder:validation:Required
+	ConfigMapSelector metav1.LabelSelector `json:"configMapSelector"`
+
+	// keys the selected ConfigMaps have to contain, under data or binaryData
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=25
+	// +listType=set
+	// +kubebuilder:validation:Required
+	Keys []string `json:"keys"`
+}
+
+// ViolationKind describes why a key is a violation
+// +kubebuilder:validation:Enum=UnapprovedKey;KeyName;MissingKey
+type ViolationKind string
+
+const (
+	// ViolationKindUnapprovedKey is a key not matched by any rule of an Allowlist EnvKeyMonitor
+	ViolationKindUnapprovedKey ViolationKind = "UnapprovedKey"
+	// ViolationKindKeyName is a key not following the keyNamePolicy of an EnvKeyMonitor
+	ViolationKindKeyName ViolationKind = "KeyName"
+	// ViolationKindMissingKey is a key listed in the requiredKeys of an EnvKeyMonitor that is
+	// missing from a selected ConfigMap
+	ViolationKindMissingKey ViolationKind = "MissingKey"
+)
+
+const (
+	// PolicyPermissive allows objects containing monitored keys to be created
+	PolicyPermissive = "PERMISSIVE"
+	// PolicyStrict forbids objects containing monitored keys from being created
+	PolicyStrict = "STRICT"
+)
+
+// ConditionEnforcing is the condition type reporting whether the STRICT policy of an EnvKeyMonitor
+// with .spec.enforceAfter is enforced yet
+const ConditionEnforcing = "Enforcing"
+
+// ConditionKeySetsResolved is the condition type reporting whether all EnvKeySet objects in
+// .spec.keySetRefs of an EnvKeyMonitor exist
+const ConditionKeySetsResolved = "KeySetsResolved"
+
+// ConditionRulesImported is the condition type reporting whether the rules of .spec.ruleSource
+// of an EnvKeyMonitor were imported
+const ConditionRulesImported = "RulesImported"
+
+// ConditionViolationsTruncated is the condition type reporting that an EnvKeyMonitor has more open
+// violations than .status.violations tracks
+const ConditionViolationsTruncated = "ViolationsTruncated"
+
+// MaintenanceWindow is a period of time in which scheduled enforcement may start
+type MaintenanceWindow struct {
+	// start is the beginning of the window
+	// +kubebuilder:validation:Required
+	Start metav1.Time `json:"start"`
+
+	// end is the end of the window, it has to be after start
+	// +kubebuilder:validation:Required
+	End metav1.Time `json:"end"`
+}
+
+// ViolationState describes the lifecycle state of a violation
+// +kubebuilder:validation:Enum=Open;Acknowledged;ResolvedByEdit;ResolvedByDelete
+type ViolationState string
+
+const (
+	// ViolationOpen is a forbidden key present in a ConfigMap
+	ViolationOpen ViolationState = "Open"
+	// ViolationAcknowledged is an open violation listed in the AcknowledgedKeysAnnotation of the ConfigMap
+	ViolationAcknowledged ViolationState = "Acknowledged"
+	// ViolationResolvedByEdit is a violation whose key was removed from the ConfigMap
+	ViolationResolvedByEdit ViolationState = "ResolvedByEdit"
+	// ViolationResolvedByDelete is a violation whose ConfigMap was deleted
+	ViolationResolvedByDelete ViolationState = "ResolvedByDelete"
+)
+
+// AcknowledgedKeysAnnotation holds a comma separated list of forbidden keys of a ConfigMap
+// whose violations are known and being worked on
+const AcknowledgedKeysAnnotation = "config.core.nvsh-ram.io/acknowledged-keys"
+
+// KeyRule describes a single monitored key
+type KeyRule struct {
+	// name is the key, prefix, suffix, substring or regular expression to look for,
+	// depending on match
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// match describes how name is compared against ConfigMap keys.
+	// Valid values are:
+	// - "Exact" (default): key must be equal to name
+	// - "Prefix": key must start with name
+	// - "Suffix": key must end with name
+	// - "Contains": key must contain name
+	// - "Regex": key must match name as a regular expression
+	// +kubebuilder:default:=Exact
+	// +optional
+	Match MatchType `json:"match,omitempty"`
+
+	// severity describes how serious a violation of this rule is
+	// +optional
+	Severity Severity `json:"severity,omitempty"`
+
+	// message is the reason shown to users when this rule is violated, overrides .spec.message.
+	// It may hold variables, see .spec.message for the available ones
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// docsURL points users to remediation hints for this rule, overrides .spec.docsURL.
+	// It may hold variables, see .spec.message for the available ones
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	DocsURL string `json:"docsURL,omitempty"`
+
+	// valueRegex is a regular expression the value of a matched key is checked against. If set,
+	// a key matched by name only violates the rule if its value matches as well
+	// +optional
+	ValueRegex string `json:"valueRegex,omitempty"`
+}
+
+// RuleSourceFormat is the format of the rules held by a rule source
+// +kubebuilder:validation:Enum=Gitleaks;DetectSecrets
+type RuleSourceFormat string
+
+const (
+	// RuleSourceGitleaks is a gitleaks TOML configuration
+	RuleSourceGitleaks RuleSourceFormat = "Gitleaks"
+	// RuleSourceDetectSecrets is a detect-secrets JSON baseline
+	RuleSourceDetectSecrets RuleSourceFormat = "DetectSecrets"
+)
+
+// RuleSource selects a ConfigMap key holding the configuration of a secret scanner
+type RuleSource struct {
+	// configMapKeyRef selects a key of a ConfigMap in the namespace of the EnvKeyMonitor
+	// +kubebuilder:validation:Required
+	ConfigMapKeyRef corev1.ConfigMapKeySelector `json:"configMapKeyRef"`
+
+	// format of the configuration
+	// +kubebuilder:validation:Required
+	Format RuleSourceFormat `json:"format"`
+}
+
+// UntranslatedRule is a rule of a rule source that cannot be fully expressed as an EnvKeyMonitor rule
+type UntranslatedRule struct {
+	// id of the rule in the rule source, e.g. a gitleaks rule id or a detect-secrets plugin name
+	ID string `json:"id"`
+
+	// reason the rule cannot be translated, or the parts of it that are not evaluated
+	Reason string `json:"reason"`
+}
+
+// RuleSourceStatus reports the outcome of importing .spec.ruleSource
+type RuleSourceStatus struct {
+	// importedRules is the number of rules imported
+	// +optional
+	ImportedRules int32 `json:"importedRules,omitempty"`
+
+	// partiallyTranslated lists the imported rules parts of which are not evaluated, the imported
+	// rules report more keys than the rules of the source
+	// +optional
+	PartiallyTranslated []UntranslatedRule `json:"partiallyTranslated,omitempty"`
+
+	// untranslated lists the rules of the source that are not evaluated
+	// +optional
+	Untranslated []UntranslatedRule `json:"untranslated,omitempty"`
+}
+
+// KeySetReference references a cluster scoped EnvKeySet
+type KeySetReference struct {
+	// name of the EnvKeySet
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// Preset names a set of rules shipped with the operator
+// +kubebuilder:validation:Enum=cloud-credentials;database;generic-secrets
+type Preset string
+
+const (
+	// PresetCloudCredentials holds the credential keys of cloud providers, e.g. AWS_SECRET_ACCESS_KEY
+	PresetCloudCredentials Preset = "cloud-credentials"
+	// PresetDatabase holds database passwords and connection strings, e.g. DATABASE_URL
+	PresetDatabase Preset = "database"
+	// PresetGenericSecrets holds keys commonly holding secrets, e.g. PASSWORD, TOKEN or PRIVATE_KEY
+	PresetGenericSecrets Preset = "generic-secrets"
+)
+
+// PresetStatus records the version of a preset whose rules were evaluated
+type PresetStatus struct {
+	// name of the preset
+	Name Preset `json:"name"`
+
+	// version of the preset shipped with the running operator
+	Version string `json:"version"`
+
+	// keyCount is the number of rules of the preset
+	KeyCount int32 `json:"keyCount"`
+}
+
+// EnvKeyMonitorSpec defines the desired state of EnvKeyMonitor
+// +kubebuilder:validation:XValidation:rule="(has(self.rules) && size(self.rules) > 0) || (has(self.keySetRefs) && size(self.keySetRefs) > 0) || (has(self.presets) && size(self.presets) > 0) || has(self.ruleSource) || has(self.keyNamePolicy) || (has(self.requiredKeys) && size(self.requiredKeys) > 0)",message="at least one of rules, keySetRefs, presets, ruleSource, keyNamePolicy or requiredKeys is required"
+// +kubebuilder:validation:XValidation:rule="!has(self.listType) || self.listType != 'Allowlist' || (!has(self.presets) && !has(self.ruleSource))",message="presets and ruleSource list forbidden keys and cannot be used with listType Allowlist"
+type EnvKeyMonitorSpec struct {
+	// rules is a list of all environmental variable keys that need to be monitored.
+	// Larger lists are kept in EnvKeySet objects referenced by keySetRefs
+	// +kubebuilder:validation:MaxItems=25
+	// +optional
+	Rules []KeyRule `json:"rules,omitempty"`
+
+	// keySetRefs references EnvKeySet objects whose rules are monitored in addition to rules
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	KeySetRefs []KeySetReference `json:"keySetRefs,omitempty"`
+
+	// presets are built-in sets of rules monitored in addition to rules, see .status.presets
+	// for the versions in use. Presets are expanded when rules are evaluated, so they follow
+	// the version shipped with the running operator
+	// +kubebuilder:validation:MaxItems=3
+	// +listType=set
+	// +optional
+	Presets []Preset `json:"presets,omitempty"`
+
+	// ruleSource imports the rules of a secret scanner configuration held in a ConfigMap, they are
+	// monitored in addition to rules. Rules that cannot be translated are listed in .status.ruleSource
+	// +optional
+	RuleSource *RuleSource `json:"ruleSource,omitempty"`
+
+	// listType describes whether rules list forbidden or approved keys.
+	// Valid values are:
+	// - "Denylist" (default): keys matched by a rule are violations
+	// - "Allowlist": keys not matched by any rule are violations, each unapproved key is reported
+	// on its own. Rules with a valueRegex only approve keys holding a matching value.
+	// This is a field of its own rather than a value of mode, since mode decides how violations
+	// are acted upon and applies to both list types, e.g. to roll out an Allowlist in Shadow mode
+	// +kubebuilder:default:=Denylist
+	// +optional
+	ListType ListType `json:"listType,omitempty"`
+
+	// keyNamePolicy is a naming convention every key of a ConfigMap has to follow, keys not
+	// following it are violations handled like keys matched by a rule
+	// +optional
+	KeyNamePolicy *KeyNamePolicy `json:"keyNamePolicy,omitempty"`
+
+	// requiredKeys lists keys that ConfigMaps selected by a label selector have to contain. Missing
+	// keys are violations handled like keys matched by a rule
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	RequiredKeys []RequiredKeys `json:"requiredKeys,omitempty"`
+
+	// Policy describes what to do if a key is found in a newly created object.
+	// Valid values are:
+	// - "PEMISSIVE" (default): allows object to be created
+	// - "STRICT": forbids object from being created
+	// +kubebuilder:validation:Enum=PERMISSIVE;STRICT
+	// +kubebuilder:default:=PERMISSIVE
+	// +kubebuilder:validation:optional
+	Policy string `json:"policy,omitempty"`
+
+	// severityPolicy maps the severity of a rule to the action taken when it is violated,
+	// overriding policy. Rules without a severity, or with a severity not listed, follow policy
+	// +listType=map
+	// +listMapKey=severity
+	// +kubebuilder:validation:MaxItems=4
+	// +optional
+	SeverityPolicy []SeverityPolicy `json:"severityPolicy,omitempty"`
+
+	// mode describes how the decisions of this EnvKeyMonitor are applied.
+	// Valid values are:
+	// - "Enforce" (default): ConfigMaps are denied or admitted with a warning as described by policy
+	// - "Audit": ConfigMaps are always admitted, violations are only recorded in events and metrics
+	// - "Shadow": ConfigMaps are always admitted, admissions that would have been denied are
+	// recorded in events, metrics and .status.shadowDenials
+	// +kubebuilder:default:=Enforce
+	// +optional
+	Mode Mode `json:"mode,omitempty"`
+
+	// enforceAfter is the time a STRICT policy starts being enforced. Before, ConfigMaps that would
+	// be denied are admitted with a warning counting down to the start of enforcement
+	// +optional
+	EnforceAfter *metav1.Time `json:"enforceAfter,omitempty"`
+
+	// maintenanceWindows restrict the start of enforcement to a maintenance window. Enforcement starts
+	// in the first window ending after enforceAfter, at enforceAfter or the start of the window,
+	// whichever is later. Only used together with enforceAfter
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// enforceOn describes which keys are enforced when a ConfigMap is updated.
+	// Valid values are:
+	// - "AddedKeys" (default): only keys added or modified by the update are enforced,
+	// keys that were already present are reported as warnings
+	// - "AllKeys": all keys are enforced, unless the update reduces the number of violations
+	// +kubebuilder:default:=AddedKeys
+	// +optional
+	EnforceOn EnforceOn `json:"enforceOn,omitempty"`
+
+	// maxNonCompliantConfigMaps is the number of existing ConfigMaps a STRICT EnvKeyMonitor may make
+	// non-compliant when it is created or updated. If exceeded, the change is rejected.
+	// If not set, the number of affected ConfigMaps is only reported as a warning
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxNonCompliantConfigMaps *int32 `json:"maxNonCompliantConfigMaps,omitempty"`
+
+	// message is the reason shown to users in admission denials, warnings and events
+	// when a rule without its own message is violated.
+	// It may hold the following variables, other template actions are rejected:
+	// - {{ .Key }}: the key found in the ConfigMap
+	// - {{ .ConfigMap }}: the name of the ConfigMap
+	// - {{ .Namespace }}: the namespace of the ConfigMap
+	// - {{ .Monitor }}: the name of this EnvKeyMonitor
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// docsURL points users to remediation hints when a rule without its own docsURL is violated.
+	// It may hold variables, see message for the available ones
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	DocsURL string `json:"docsURL,omitempty"`
+
+	// deletionProtection rejects deleting this EnvKeyMonitor while set to true.
+	// It has to be set to false before the object can be deleted
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// bypass lists users, groups and ServiceAccounts whose requests are admitted with a warning
+	// instead of being denied by this EnvKeyMonitor, e.g. during a migration.
+	// Every bypass is recorded as a ForbiddenKeyBypassed event and in metrics
+	// +optional
+	Bypass *Bypass `json:"bypass,omitempty"`
+
+	// notificationTargetRefs lists NotificationTarget objects in the same namespace that
+	// violations of this EnvKeyMonitor are delivered to
+	// +kubebuilder:validation:MaxItems=5
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	NotificationTargetRefs []corev1.LocalObjectReference `json:"notificationTargetRefs,omitempty"`
+}
+
+// SharedKey describes a rule whose name is also monitored by other EnvKeyMonitor objects in the namespace
+type SharedKey struct {
+	// name is the name of the rule
+	Name string `json:"name"`
+
+	// match is the match type of the rule
+	// +optional
+	Match MatchType `json:"match,omitempty"`
+
+	// monitors are the names of the other EnvKeyMonitor objects monitoring the key
+	Monitors []string `json:"monitors"`
+}
+
+// UnmonitoredKeys records the keys that are no longer monitored in the namespace after an
+// EnvKeyMonitor object was deleted
+type UnmonitoredKeys struct {
+	// monitor is the name of the deleted EnvKeyMonitor
+	Monitor string `json:"monitor"`
+
+	// keys are the rules of the deleted EnvKeyMonitor not held by any other object
+	Keys []string `json:"keys"`
+
+	// deletionTime is the time the EnvKeyMonitor was deleted
+	DeletionTime metav1.Time `json:"deletionTime"`
+}
+
+// Requester describes the user of an admission request
+type Requester struct {
+	// username of the requesting user
+	Username string `json:"username"`
+
+	// groups of the requesting user
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+
+	// fieldManager of the request, if set by the client
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+}
+
+// ViolationRecord tracks the lifecycle of a forbidden key found in a ConfigMap
+type ViolationRecord struct {
+	// configMap is the name of the ConfigMap containing the key
+	ConfigMap string `json:"configMap"`
+
+	// key is the forbidden key
+	Key string `json:"key"`
+
+	// rule is the name of the rule matching the key, empty for violations of another kind
+	Rule string `json:"rule"`
+
+	// kind is why the key is a violation, empty for keys matched by a rule
+	// +optional
+	Kind ViolationKind `json:"kind,omitempty"`
+
+	// severity is the severity of the rule matching the key
+	// +optional
+	Severity Severity `json:"severity,omitempty"`
+
+	// state is the lifecycle state of the violation
+	State ViolationState `json:"state"`
+
+	// openedAt is the time the violation was first seen
+	OpenedAt metav1.Time `json:"openedAt"`
+
+	// acknowledgedAt is the time the key was listed in the AcknowledgedKeysAnnotation of the ConfigMap
+	// +optional
+	AcknowledgedAt *metav1.Time `json:"acknowledgedAt,omitempty"`
+
+	// resolvedAt is the time the key or the ConfigMap was removed
+	// +optional
+	ResolvedAt *metav1.Time `json:"resolvedAt,omitempty"`
+
+	// admittedBy is the user of the most recent request admitting the ConfigMap with the key
+	// +optional
+	AdmittedBy *Requester `json:"admittedBy,omitempty"`
+}
+
+// PendingAdmission is the user admitting a forbidden key whose violation is not tracked yet, it is
+// recorded on the violation once the ConfigMap is seen by the controller
+type PendingAdmission struct {
+	// configMap is the name of the admitted ConfigMap
+	ConfigMap string `json:"configMap"`
+
+	// key is the forbidden key
+	Key string `json:"key"`
+
+	// rule is the name of the rule matching the key, empty for violations of another kind
+	// +optional
+	Rule string `json:"rule,omitempty"`
+
+	// kind is why the key is a violation, empty for keys matched by a rule
+	// +optional
+	Kind ViolationKind `json:"kind,omitempty"`
+
+	// admittedBy is the user of the admission request
+	AdmittedBy Requester `json:"admittedBy"`
+
+	// time is the time of the admission request
+	Time metav1.Time `json:"time"`
+}
+
+// ShadowDenials records the admissions a Shadow EnvKeyMonitor would have denied
+type ShadowDenials struct {
+	// count is the number of forbidden keys that would have been denied
+	Count int64 `json:"count"`
+
+	// lastDenialTime is the time of the most recent admission that would have been denied
+	// +optional
+	LastDenialTime *metav1.Time `json:"lastDenialTime,omitempty"`
+
+	// configMaps are the most recent ConfigMaps that would have been denied
+	// +optional
+	ConfigMaps []string `json:"configMaps,omitempty"`
+}
+
+// EnvKeyMonitorStatus defines the observed state of EnvKeyMonitor.
+type EnvKeyMonitorStatus struct {
+	// For Kubernetes API conventions, see:
+	// https://github.com/kubernetes/community/blob/master/contributors/devel/sig-architecture/api-conventions.md#typical-status-properties
+
+	// conditions represent the current state of the EnvKeyMonitor resource.
+	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
+	//
+	// Standard condition types include:
+	// - "Available": the resource is fully functional
+	// - "Progressing": the resource is being created or updated
+	// - "Degraded": the resource failed to reach or maintain its desired state
+	//
+	// The status of each condition is one of True, False, or Unknown.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// sharedKeys lists the rules of this EnvKeyMonitor that are also monitored by other
+	// EnvKeyMonitor objects in the namespace. Overlapping rules are evaluated as a union,
+	// a key stays monitored as long as one of the objects holding it exists
+	// +optional
+	SharedKeys []SharedKey `json:"sharedKeys,omitempty"`
+
+	// unmonitoredKeys lists the keys that stopped being monitored in the namespace when other
+	// EnvKeyMonitor objects were deleted, most recent last
+	// +optional
+	UnmonitoredKeys []UnmonitoredKeys `json:"unmonitoredKeys,omitempty"`
+
+	// violations tracks the open violations of this EnvKeyMonitor and the most recently resolved ones.
+	// At most 100 open violations are tracked, see openViolationCount for the number of all of them
+	// +optional
+	Violations []ViolationRecord `json:"violations,omitempty"`
+
+	// openViolationCount is the number of open violations of this EnvKeyMonitor, including the ones
+	// not tracked in violations
+	// +optional
+	OpenViolationCount int32 `json:"openViolationCount,omitempty"`
+
+	// meanTimeToRemediate is the mean time between opening and resolving the violations
+	// recorded by all EnvKeyMonitor objects in the namespace
+	// +optional
+	MeanTimeToRemediate *metav1.Duration `json:"meanTimeToRemediate,omitempty"`
+
+	// shadowDenials records the admissions this EnvKeyMonitor would have denied in Shadow mode
+	// +optional
+	ShadowDenials *ShadowDenials `json:"shadowDenials,omitempty"`
+
+	// pendingAdmissions are the users admitting forbidden keys whose violations are not tracked yet,
+	// they are dropped if the violation is not found within 5 minutes, e.g. if another webhook
+	// rejected the ConfigMap
+	// +optional
+	PendingAdmissions []PendingAdmission `json:"pendingAdmissions,omitempty"`
+
+	// effectiveKeyCount is the number of rules evaluated, including the rules of presets, referenced
+	// EnvKeySet objects and the rule source
+	// +optional
+	EffectiveKeyCount int32 `json:"effectiveKeyCount,omitempty"`
+
+	// presets lists the presets in .spec.presets with the version whose rules were evaluated
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Presets []PresetStatus `json:"presets,omitempty"`
+
+	// ruleSource reports the rules imported from .spec.ruleSource
+	// +optional
+	RuleSource *RuleSourceStatus `json:"ruleSource,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:resource:shortName=ekm;ekms
+// +kubebuilder:printcolumn:name="Keys",type=integer,JSONPath=`.status.effectiveKeyCount`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// EnvKeyMonitor is the Schema for the envkeymonitors API
+type EnvKeyMonitor struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of EnvKeyMonitor
+	// +required
+	Spec EnvKeyMonitorSpec `json:"spec"`
+
+	// status defines the observed state of EnvKeyMonitor
+	// +optional
+	Status EnvKeyMonitorStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnvKeyMonitorList contains a list of EnvKeyMonitor
+type EnvKeyMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []EnvKeyMonitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EnvKeyMonitor{}, &EnvKeyMonitorList{})
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnvKeySetSpec defines the desired state of EnvKeySet
+type EnvKeySetSpec struct {
+	// rules is a list of environmental variable keys shared by the EnvKeyMonitor objects referencing
+	// this EnvKeySet. Rules matching by regular expression with an invalid expression never match
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=1000
+	// +kubebuilder:validation:Required
+	Rules []KeyRule `json:"rules"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:resource:shortName=eks
+// EnvKeySet is the Schema for the envkeysets API. It holds rules shared by EnvKeyMonitor objects
+// in all namespaces, and is not limited to the 25 rules of an EnvKeyMonitor
+type EnvKeySet struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of EnvKeySet
+	// +required
+	Spec EnvKeySetSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// EnvKeySetList contains a list of EnvKeySet
+type EnvKeySetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []EnvKeySet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EnvKeySet{}, &EnvKeySetList{})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NotificationSignatureHeader holds the HMAC-SHA256 signature of a notification, formatted as
+// "sha256=<hex encoded signature>"
+const NotificationSignatureHeader = "X-Signature-256"
+
+// NotificationRetry describes how failed deliveries are retried
+type NotificationRetry struct {
+	// maxAttempts is the number of delivery attempts before a batch is dropped
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default:=5
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// initialBackoff is the time waited before the first retry, it doubles with every attempt
+	// +kubebuilder:default:="1s"
+	// +optional
+	InitialBackoff metav1.Duration `json:"initialBackoff,omitempty"`
+}
+
+// NotificationTargetSpec defines the desired state of NotificationTarget
+type NotificationTargetSpec struct {
+	// url the notifications are sent to with an HTTP POST request and a JSON payload
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// hmacSecretRef selects a key of a Secret in the namespace of the NotificationTarget.
+	// If set, the payload is signed with HMAC-SHA256 using the key, the signature is sent
+	// in the X-Signature-256 header
+	// +optional
+	HMACSecretRef *corev1.SecretKeySelector `json:"hmacSecretRef,omitempty"`
+
+	// batchInterval is the time violations are collected before they are sent in a single request
+	// +kubebuilder:default:="10s"
+	// +optional
+	BatchInterval metav1.Duration `json:"batchInterval,omitempty"`
+
+	// retry describes how failed deliveries are retried
+	// +optional
+	Retry NotificationRetry `json:"retry,omitempty"`
+}
+
+// NotificationTargetStatus defines the observed state of NotificationTarget.
+type NotificationTargetStatus struct {
+	// conditions represent the current state of the NotificationTarget resource.
+	// The "Ready" condition reports whether the last delivery succeeded
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// lastDeliveryTime is the time of the last successful delivery
+	// +optional
+	LastDeliveryTime *metav1.Time `json:"lastDeliveryTime,omitempty"`
+
+	// delivered is the number of violations delivered
+	// +optional
+	Delivered int64 `json:"delivered,omitempty"`
+
+	// dropped is the number of violations dropped after all delivery attempts failed
+	// +optional
+	Dropped int64 `json:"dropped,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:resource:shortName=nt;nts
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+// +kubebuilder:printcolumn:name="Delivered",type=integer,JSONPath=`.status.delivered`
+// NotificationTarget is the Schema for the notificationtargets API
+type NotificationTarget struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of NotificationTarget
+	// +required
+	Spec NotificationTargetSpec `json:"spec"`
+
+	// status defines the observed state of NotificationTarget
+	// +optional
+	Status NotificationTargetStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationTargetList contains a list of NotificationTarget
+type NotificationTargetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []NotificationTarget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationTarget{}, &NotificationTargetList{})
+}
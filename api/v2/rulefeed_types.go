@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RuleFeedSignatureHeader holds the base64 encoded Ed25519 signature of a rule feed response body
+const RuleFeedSignatureHeader = "X-Signature-Ed25519"
+
+// RuleFeedSpec defines the desired state of RuleFeed
+type RuleFeedSpec struct {
+	// url the feed is fetched from with an HTTP GET request. The response is a JSON document of
+	// the form {"version": "...", "rules": [...]}, rules have the fields of .spec.rules of an EnvKeySet
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// interval is the time between two polls of the feed
+	// +kubebuilder:default:="5m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// publicKey is the base64 encoded Ed25519 public key of the feed. If set, responses are only
+	// accepted with a valid signature of the body in the X-Signature-Ed25519 header
+	// +optional
+	PublicKey string `json:"publicKey,omitempty"`
+
+	// keySetName is the name of the EnvKeySet the rules of the feed are written to, defaults to the
+	// name of the RuleFeed. EnvKeyMonitor objects reference it in .spec.keySetRefs
+	// +optional
+	KeySetName string `json:"keySetName,omitempty"`
+}
+
+// RuleFeedStatus defines the observed state of RuleFeed.
+type RuleFeedStatus struct {
+	// conditions represent the current state of the RuleFeed resource.
+	// The "Ready" condition reports whether the last poll succeeded
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// etag of the response the EnvKeySet was written from, sent in If-None-Match when polling
+	// +optional
+	ETag string `json:"etag,omitempty"`
+
+	// rulesHash is the SHA-256 hash of the rules written to the EnvKeySet. The etag is only sent
+	// while the EnvKeySet holds these rules, an EnvKeySet changed by hand is written again
+	// +optional
+	RulesHash string `json:"rulesHash,omitempty"`
+
+	// version of the feed written to the EnvKeySet, the last known good version if a poll failed
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ruleCount is the number of rules written to the EnvKeySet
+	// +optional
+	RuleCount int32 `json:"ruleCount,omitempty"`
+
+	// lastSyncTime is the time the EnvKeySet was last written
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// lastPollTime is the time the feed was last polled, successfully or not
+	// +optional
+	LastPollTime *metav1.Time `json:"lastPollTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:resource:shortName=rf
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.version`
+// +kubebuilder:printcolumn:name="Rules",type=integer,JSONPath=`.status.ruleCount`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+// RuleFeed is the Schema for the rulefeeds API. The controller polls the feed and writes its rules
+// to an EnvKeySet, which keeps the last known good rules while the feed is unavailable
+type RuleFeed struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of RuleFeed
+	// +required
+	Spec RuleFeedSpec `json:"spec"`
+
+	// status defines the observed state of RuleFeed
+	// +optional
+	Status RuleFeedStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// RuleFeedList contains a list of RuleFeed
+type RuleFeedList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []RuleFeed `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RuleFeed{}, &RuleFeedList{})
+}
@@ -0,0 +1,1030 @@
+//go:build !ignore_autogenerated
+
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Bypass) DeepCopyInto(out *Bypass) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]BypassServiceAccount, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Bypass.
+func (in *Bypass) DeepCopy() *Bypass {
+	if in == nil {
+		return nil
+	}
+	out := new(Bypass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BypassServiceAccount) DeepCopyInto(out *BypassServiceAccount) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BypassServiceAccount.
+func (in *BypassServiceAccount) DeepCopy() *BypassServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(BypassServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyBaseline) DeepCopyInto(out *EnvKeyBaseline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyBaseline.
+func (in *EnvKeyBaseline) DeepCopy() *EnvKeyBaseline {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyBaseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvKeyBaseline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyBaselineList) DeepCopyInto(out *EnvKeyBaselineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EnvKeyBaseline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyBaselineList.
+func (in *EnvKeyBaselineList) DeepCopy() *EnvKeyBaselineList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyBaselineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvKeyBaselineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyBaselineSpec) DeepCopyInto(out *EnvKeyBaselineSpec) {
+	*out = *in
+	out.Duration = in.Duration
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyBaselineSpec.
+func (in *EnvKeyBaselineSpec) DeepCopy() *EnvKeyBaselineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyBaselineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyBaselineStatus) DeepCopyInto(out *EnvKeyBaselineStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]ObservedKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastInventoryTime != nil {
+		in, out := &in.LastInventoryTime, &out.LastInventoryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Proposal != nil {
+		in, out := &in.Proposal, &out.Proposal
+		*out = new(EnvKeyMonitorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyBaselineStatus.
+func (in *EnvKeyBaselineStatus) DeepCopy() *EnvKeyBaselineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyBaselineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyMonitor) DeepCopyInto(out *EnvKeyMonitor) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyMonitor.
+func (in *EnvKeyMonitor) DeepCopy() *EnvKeyMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvKeyMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyMonitorList) DeepCopyInto(out *EnvKeyMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EnvKeyMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyMonitorList.
+func (in *EnvKeyMonitorList) DeepCopy() *EnvKeyMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvKeyMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyMonitorSpec) DeepCopyInto(out *EnvKeyMonitorSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]KeyRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeySetRefs != nil {
+		in, out := &in.KeySetRefs, &out.KeySetRefs
+		*out = make([]KeySetReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Presets != nil {
+		in, out := &in.Presets, &out.Presets
+		*out = make([]Preset, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuleSource != nil {
+		in, out := &in.RuleSource, &out.RuleSource
+		*out = new(RuleSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeyNamePolicy != nil {
+		in, out := &in.KeyNamePolicy, &out.KeyNamePolicy
+		*out = new(KeyNamePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequiredKeys != nil {
+		in, out := &in.RequiredKeys, &out.RequiredKeys
+		*out = make([]RequiredKeys, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SeverityPolicy != nil {
+		in, out := &in.SeverityPolicy, &out.SeverityPolicy
+		*out = make([]SeverityPolicy, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnforceAfter != nil {
+		in, out := &in.EnforceAfter, &out.EnforceAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaxNonCompliantConfigMaps != nil {
+		in, out := &in.MaxNonCompliantConfigMaps, &out.MaxNonCompliantConfigMaps
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Bypass != nil {
+		in, out := &in.Bypass, &out.Bypass
+		*out = new(Bypass)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotificationTargetRefs != nil {
+		in, out := &in.NotificationTargetRefs, &out.NotificationTargetRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyMonitorSpec.
+func (in *EnvKeyMonitorSpec) DeepCopy() *EnvKeyMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeyMonitorStatus) DeepCopyInto(out *EnvKeyMonitorStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SharedKeys != nil {
+		in, out := &in.SharedKeys, &out.SharedKeys
+		*out = make([]SharedKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UnmonitoredKeys != nil {
+		in, out := &in.UnmonitoredKeys, &out.UnmonitoredKeys
+		*out = make([]UnmonitoredKeys, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]ViolationRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MeanTimeToRemediate != nil {
+		in, out := &in.MeanTimeToRemediate, &out.MeanTimeToRemediate
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ShadowDenials != nil {
+		in, out := &in.ShadowDenials, &out.ShadowDenials
+		*out = new(ShadowDenials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingAdmissions != nil {
+		in, out := &in.PendingAdmissions, &out.PendingAdmissions
+		*out = make([]PendingAdmission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Presets != nil {
+		in, out := &in.Presets, &out.Presets
+		*out = make([]PresetStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.RuleSource != nil {
+		in, out := &in.RuleSource, &out.RuleSource
+		*out = new(RuleSourceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeyMonitorStatus.
+func (in *EnvKeyMonitorStatus) DeepCopy() *EnvKeyMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeyMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeySet) DeepCopyInto(out *EnvKeySet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeySet.
+func (in *EnvKeySet) DeepCopy() *EnvKeySet {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeySet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvKeySet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeySetList) DeepCopyInto(out *EnvKeySetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EnvKeySet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeySetList.
+func (in *EnvKeySetList) DeepCopy() *EnvKeySetList {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeySetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *EnvKeySetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvKeySetSpec) DeepCopyInto(out *EnvKeySetSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]KeyRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvKeySetSpec.
+func (in *EnvKeySetSpec) DeepCopy() *EnvKeySetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvKeySetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyNamePolicy) DeepCopyInto(out *KeyNamePolicy) {
+	*out = *in
+	if in.MaxLength != nil {
+		in, out := &in.MaxLength, &out.MaxLength
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyNamePolicy.
+func (in *KeyNamePolicy) DeepCopy() *KeyNamePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyNamePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyRule) DeepCopyInto(out *KeyRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyRule.
+func (in *KeyRule) DeepCopy() *KeyRule {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeySetReference) DeepCopyInto(out *KeySetReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySetReference.
+func (in *KeySetReference) DeepCopy() *KeySetReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KeySetReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationRetry) DeepCopyInto(out *NotificationRetry) {
+	*out = *in
+	out.InitialBackoff = in.InitialBackoff
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationRetry.
+func (in *NotificationRetry) DeepCopy() *NotificationRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTarget) DeepCopyInto(out *NotificationTarget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTarget.
+func (in *NotificationTarget) DeepCopy() *NotificationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationTarget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTargetList) DeepCopyInto(out *NotificationTargetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NotificationTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTargetList.
+func (in *NotificationTargetList) DeepCopy() *NotificationTargetList {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTargetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationTargetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTargetSpec) DeepCopyInto(out *NotificationTargetSpec) {
+	*out = *in
+	if in.HMACSecretRef != nil {
+		in, out := &in.HMACSecretRef, &out.HMACSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.BatchInterval = in.BatchInterval
+	out.Retry = in.Retry
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTargetSpec.
+func (in *NotificationTargetSpec) DeepCopy() *NotificationTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTargetStatus) DeepCopyInto(out *NotificationTargetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastDeliveryTime != nil {
+		in, out := &in.LastDeliveryTime, &out.LastDeliveryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTargetStatus.
+func (in *NotificationTargetStatus) DeepCopy() *NotificationTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObservedKey) DeepCopyInto(out *ObservedKey) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]KeySource, len(*in))
+		copy(*out, *in)
+	}
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObservedKey.
+func (in *ObservedKey) DeepCopy() *ObservedKey {
+	if in == nil {
+		return nil
+	}
+	out := new(ObservedKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingAdmission) DeepCopyInto(out *PendingAdmission) {
+	*out = *in
+	in.AdmittedBy.DeepCopyInto(&out.AdmittedBy)
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingAdmission.
+func (in *PendingAdmission) DeepCopy() *PendingAdmission {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingAdmission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PresetStatus) DeepCopyInto(out *PresetStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PresetStatus.
+func (in *PresetStatus) DeepCopy() *PresetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PresetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Requester) DeepCopyInto(out *Requester) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Requester.
+func (in *Requester) DeepCopy() *Requester {
+	if in == nil {
+		return nil
+	}
+	out := new(Requester)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredKeys) DeepCopyInto(out *RequiredKeys) {
+	*out = *in
+	in.ConfigMapSelector.DeepCopyInto(&out.ConfigMapSelector)
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredKeys.
+func (in *RequiredKeys) DeepCopy() *RequiredKeys {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredKeys)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleFeed) DeepCopyInto(out *RuleFeed) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleFeed.
+func (in *RuleFeed) DeepCopy() *RuleFeed {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleFeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuleFeed) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleFeedList) DeepCopyInto(out *RuleFeedList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RuleFeed, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleFeedList.
+func (in *RuleFeedList) DeepCopy() *RuleFeedList {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleFeedList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RuleFeedList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleFeedSpec) DeepCopyInto(out *RuleFeedSpec) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleFeedSpec.
+func (in *RuleFeedSpec) DeepCopy() *RuleFeedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleFeedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleFeedStatus) DeepCopyInto(out *RuleFeedStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastPollTime != nil {
+		in, out := &in.LastPollTime, &out.LastPollTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleFeedStatus.
+func (in *RuleFeedStatus) DeepCopy() *RuleFeedStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleFeedStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleSource) DeepCopyInto(out *RuleSource) {
+	*out = *in
+	in.ConfigMapKeyRef.DeepCopyInto(&out.ConfigMapKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleSource.
+func (in *RuleSource) DeepCopy() *RuleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleSourceStatus) DeepCopyInto(out *RuleSourceStatus) {
+	*out = *in
+	if in.PartiallyTranslated != nil {
+		in, out := &in.PartiallyTranslated, &out.PartiallyTranslated
+		*out = make([]UntranslatedRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Untranslated != nil {
+		in, out := &in.Untranslated, &out.Untranslated
+		*out = make([]UntranslatedRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleSourceStatus.
+func (in *RuleSourceStatus) DeepCopy() *RuleSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeverityPolicy) DeepCopyInto(out *SeverityPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeverityPolicy.
+func (in *SeverityPolicy) DeepCopy() *SeverityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SeverityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowDenials) DeepCopyInto(out *ShadowDenials) {
+	*out = *in
+	if in.LastDenialTime != nil {
+		in, out := &in.LastDenialTime, &out.LastDenialTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowDenials.
+func (in *ShadowDenials) DeepCopy() *ShadowDenials {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowDenials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedKey) DeepCopyInto(out *SharedKey) {
+	*out = *in
+	if in.Monitors != nil {
+		in, out := &in.Monitors, &out.Monitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedKey.
+func (in *SharedKey) DeepCopy() *SharedKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnmonitoredKeys) DeepCopyInto(out *UnmonitoredKeys) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.DeletionTime.DeepCopyInto(&out.DeletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnmonitoredKeys.
+func (in *UnmonitoredKeys) DeepCopy() *UnmonitoredKeys {
+	if in == nil {
+		return nil
+	}
+	out := new(UnmonitoredKeys)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UntranslatedRule) DeepCopyInto(out *UntranslatedRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UntranslatedRule.
+func (in *UntranslatedRule) DeepCopy() *UntranslatedRule {
+	if in == nil {
+		return nil
+	}
+	out := new(UntranslatedRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ViolationRecord) DeepCopyInto(out *ViolationRecord) {
+	*out = *in
+	in.OpenedAt.DeepCopyInto(&out.OpenedAt)
+	if in.AcknowledgedAt != nil {
+		in, out := &in.AcknowledgedAt, &out.AcknowledgedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ResolvedAt != nil {
+		in, out := &in.ResolvedAt, &out.ResolvedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.AdmittedBy != nil {
+		in, out := &in.AdmittedBy, &out.AdmittedBy
+		*out = new(Requester)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ViolationRecord.
+func (in *ViolationRecord) DeepCopy() *ViolationRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ViolationRecord)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/importer"
+)
+
+// Name of the subcommand translating a secret scanner configuration
+const importRulesCommand = "import-rules"
+
+// Maximum number of rules of an EnvKeySet
+const maxKeySetRules = 1000
+
+// Print an EnvKeySet holding the rules translated from a secret scanner configuration, rules that
+// cannot be translated are reported on stderr. Returns the exit code
+func importRules(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet(importRulesCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", string(configv2.RuleSourceGitleaks),
+		"Format of the configuration, Gitleaks for a gitleaks TOML configuration "+
+			"or DetectSecrets for a detect-secrets JSON baseline.")
+	file := flags.String("file", "-", "Path of the configuration, - reads it from stdin.")
+	name := flags.String("name", "imported-rules", "Name of the printed EnvKeySet.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Cannot read configuration: %v\n", err)
+		return 1
+	}
+
+	result, err := importer.Import(configv2.RuleSourceFormat(*format), data)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+	for _, partial := range result.PartiallyTranslated {
+		_, _ = fmt.Fprintf(stderr, "Partially translated %s: %s\n", partial.ID, partial.Reason)
+	}
+	for _, untranslated := range result.Untranslated {
+		_, _ = fmt.Fprintf(stderr, "Cannot translate %s: %s\n", untranslated.ID, untranslated.Reason)
+	}
+	if len(result.Rules) == 0 || len(result.Rules) > maxKeySetRules {
+		_, _ = fmt.Fprintf(stderr, "Translated %d rules, an EnvKeySet holds 1 to %d rules\n", len(result.Rules), maxKeySetRules)
+		return 1
+	}
+
+	out, err := yaml.Marshal(configv2.EnvKeySet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: configv2.GroupVersion.String(), Kind: "EnvKeySet"},
+		ObjectMeta: metav1.ObjectMeta{Name: *name},
+		Spec:       configv2.EnvKeySetSpec{Rules: result.Rules},
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Cannot print EnvKeySet: %v\n", err)
+		return 1
+	}
+	_, _ = stdout.Write(out)
+	_, _ = fmt.Fprintf(
+		stderr,
+		"Translated %d rules, %d of them partially, %d rules cannot be translated\n",
+		len(result.Rules),
+		len(result.PartiallyTranslated),
+		len(result.Untranslated),
+	)
+	return 0
+}
@@ -19,7 +19,9 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"net/http"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -36,8 +38,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	configv1 "github.com/Nivesh00/config-keys-operator.git/api/v1"
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/audit"
 	"github.com/Nivesh00/config-keys-operator.git/internal/controller"
+	"github.com/Nivesh00/config-keys-operator.git/internal/notify"
 	webhookv1 "github.com/Nivesh00/config-keys-operator.git/internal/webhook/v1"
+	webhookv2 "github.com/Nivesh00/config-keys-operator.git/internal/webhook/v2"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -50,11 +56,17 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(configv1.AddToScheme(scheme))
+	utilruntime.Must(configv2.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 // nolint:gocyclo
 func main() {
+	// Translate a secret scanner configuration instead of running the manager
+	if len(os.Args) > 1 && os.Args[1] == importRulesCommand {
+		os.Exit(importRules(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	var metricsAddr string
 	var metricsCertPath, metricsCertName, metricsCertKey string
 	var webhookCertPath, webhookCertName, webhookCertKey string
@@ -62,6 +74,8 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var auditLogPath string
+	var notificationAllowedNetworks string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -80,6 +94,12 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&auditLogPath, "audit-log-path", "",
+		"If set, every ConfigMap admission decision is written as a JSON line to this file. "+
+			"Use - to write to stdout.")
+	flag.StringVar(&notificationAllowedNetworks, "notification-allowed-networks", "",
+		"Comma separated CIDRs of internal networks NotificationTarget objects may send to, e.g. 10.96.0.0/12. "+
+			"Loopback, private, link-local and other non-public addresses are denied unless they are listed.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -180,22 +200,51 @@ func main() {
 	}
 
 	if err := (&controller.EnvKeyMonitorReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("envkeymonitor-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "EnvKeyMonitor")
 		os.Exit(1)
 	}
+	if err := (&controller.RuleFeedReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RuleFeed")
+		os.Exit(1)
+	}
+	if err := (&controller.EnvKeyBaselineReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		APIReader: mgr.GetAPIReader(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "EnvKeyBaseline")
+		os.Exit(1)
+	}
+	var auditLog *audit.Logger
+	if auditLogPath != "" {
+		if auditLog, err = audit.Open(auditLogPath); err != nil {
+			setupLog.Error(err, "unable to open audit log")
+			os.Exit(1)
+		}
+	}
+	allowedNetworks, err := notify.ParseAllowedNetworks(notificationAllowedNetworks)
+	if err != nil {
+		setupLog.Error(err, "unable to parse allowed networks of notification targets")
+		os.Exit(1)
+	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err := webhookv1.SetupConfigMapWebhookWithManager(mgr); err != nil {
+		if err := webhookv1.SetupConfigMapWebhookWithManager(mgr, auditLog, allowedNetworks); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ConfigMap")
 			os.Exit(1)
 		}
 	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err := webhookv1.SetupEnvKeyMonitorWebhookWithManager(mgr); err != nil {
+		if err := webhookv2.SetupEnvKeyMonitorWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "EnvKeyMonitor")
 			os.Exit(1)
 		}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit writes a JSON lines stream recording every admission decision on ConfigMaps.
+// Records hold key names only, key values are never written.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var auditLog = logf.Log.WithName("audit")
+
+// Decisions recorded in the audit stream
+const (
+	// DecisionAllowed admits the request without warnings
+	DecisionAllowed = "allowed"
+	// DecisionWarned admits the request with warnings
+	DecisionWarned = "warned"
+	// DecisionDenied rejects the request
+	DecisionDenied = "denied"
+)
+
+// Record describes a single admission decision
+type Record struct {
+	Time   time.Time `json:"time"`
+	UID    string    `json:"uid"`
+	User   string    `json:"user"`
+	Groups []string  `json:"groups"`
+	// FieldManager of the request, if set by the client
+	FieldManager string `json:"fieldManager,omitempty"`
+	Operation    string `json:"operation"`
+	// DryRun is set for requests that are not persisted, e.g. kubectl apply --dry-run=server
+	DryRun    bool   `json:"dryRun"`
+	Namespace string `json:"namespace"`
+	ConfigMap string `json:"configMap"`
+	// Monitors and rules matching keys of the ConfigMap, sorted by name
+	Monitors []string `json:"monitors"`
+	Rules    []string `json:"rules"`
+	// Keys matched by the rules, values are never recorded
+	Keys     []string `json:"keys"`
+	Decision string   `json:"decision"`
+	// LatencyMilliseconds is the time taken to decide
+	LatencyMilliseconds float64 `json:"latencyMs"`
+}
+
+// Logger writes records as JSON lines, it is safe for concurrent use
+type Logger struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewLogger returns a Logger writing to w
+func NewLogger(w io.Writer) *Logger {
+	return &Logger{encoder: json.NewEncoder(w)}
+}
+
+// Open returns a Logger writing to stdout if path is "-", or appending to the file at path
+func Open(path string) (*Logger, error) {
+
+	if path == "-" {
+		return NewLogger(os.Stdout), nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot open audit log %s: %v", path, err)
+	}
+	return NewLogger(file), nil
+}
+
+// Log writes a record. Calling Log on a nil Logger does nothing, records that cannot be written
+// are reported in the operator log
+func (l *Logger) Log(record Record) {
+
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.encoder.Encode(record); err != nil {
+		auditLog.Error(err, "Cannot write audit record", "uid", record.UID)
+	}
+}
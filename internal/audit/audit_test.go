@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logger", func() {
+	It("Should write one JSON object per line", func() {
+		var buffer bytes.Buffer
+		logger := NewLogger(&buffer)
+		logger.Log(Record{UID: "1", Decision: DecisionDenied, Keys: []string{"API_KEY"}})
+		logger.Log(Record{UID: "2", Decision: DecisionAllowed})
+
+		lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		var record Record
+		Expect(json.Unmarshal([]byte(lines[0]), &record)).To(Succeed())
+		Expect(record.UID).To(Equal("1"))
+		Expect(record.Decision).To(Equal(DecisionDenied))
+		Expect(record.Keys).To(ConsistOf("API_KEY"))
+	})
+
+	It("Should ignore records on a nil logger", func() {
+		var logger *Logger
+		Expect(func() { logger.Log(Record{UID: "1"}) }).NotTo(Panic())
+	})
+})
@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package baseline inventories the keys used in a namespace and proposes an EnvKeyMonitor from
+// the keys seen while an EnvKeyBaseline is learning.
+package baseline
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/presets"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+const (
+	// MaxKeys is the number of keys recorded in the status of an EnvKeyBaseline
+	MaxKeys = 1000
+	// Number of rules of a proposed EnvKeyMonitor
+	maxRules = 25
+	// Number of approved keys sharing a prefix that are proposed as a single Prefix rule
+	minPrefixKeys = 3
+)
+
+// Presets keys are classified by, in the order they are checked
+var classifiers = []configv2.Preset{
+	configv2.PresetCloudCredentials,
+	configv2.PresetDatabase,
+	configv2.PresetGenericSecrets,
+}
+
+// Inventory returns the keys of the ConfigMaps and the names of the environment variables set
+// by the containers of the Pods, with the sources they were found in
+func Inventory(configmaps []corev1.ConfigMap, pods []corev1.Pod) map[string][]configv2.KeySource {
+
+	inventory := map[string][]configv2.KeySource{}
+	add := func(name string, source configv2.KeySource) {
+		if !slices.Contains(inventory[name], source) {
+			inventory[name] = append(inventory[name], source)
+		}
+	}
+	for _, configmap := range configmaps {
+		for key := range configmap.Data {
+			add(key, configv2.KeySourceConfigMap)
+		}
+		for key := range configmap.BinaryData {
+			add(key, configv2.KeySourceConfigMap)
+		}
+	}
+	for _, pod := range pods {
+		for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+			for _, container := range containers {
+				for _, env := range container.Env {
+					add(env.Name, configv2.KeySourceWorkload)
+				}
+			}
+		}
+	}
+	return inventory
+}
+
+// Merge adds the keys of an inventory to the keys seen before and classifies them, ordered by
+// name. Keys seen before are kept, new keys are dropped once MaxKeys keys are recorded. Returns
+// the number of dropped keys
+func Merge(
+	keys []configv2.ObservedKey,
+	inventory map[string][]configv2.KeySource,
+	now metav1.Time,
+) ([]configv2.ObservedKey, int) {
+
+	merged := make([]configv2.ObservedKey, 0, len(keys))
+	seen := map[string]bool{}
+	for _, key := range keys {
+		seen[key.Name] = true
+		if sources, ok := inventory[key.Name]; ok {
+			for _, source := range sources {
+				if !slices.Contains(key.Sources, source) {
+					key.Sources = append(key.Sources, source)
+				}
+			}
+			key.LastSeen = now
+		}
+		merged = append(merged, key)
+	}
+
+	dropped := 0
+	for _, name := range slices.Sorted(maps.Keys(inventory)) {
+		if seen[name] {
+			continue
+		}
+		if len(merged) >= MaxKeys {
+			dropped++
+			continue
+		}
+		merged = append(merged, configv2.ObservedKey{
+			Name:      name,
+			Sources:   slices.Clone(inventory[name]),
+			FirstSeen: now,
+			LastSeen:  now,
+		})
+	}
+
+	// Classify every key, presets may have changed since a key was first seen
+	for i := range merged {
+		slices.Sort(merged[i].Sources)
+		merged[i].Preset, _ = Classify(merged[i].Name)
+	}
+	slices.SortFunc(merged, func(a, b configv2.ObservedKey) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return merged, dropped
+}
+
+// Classify returns the preset whose rules match a key. Keys are compared in upper case with '-'
+// and '.' replaced by '_', so 'db-password' is as suspicious as 'DB_PASSWORD'
+func Classify(name string) (configv2.Preset, bool) {
+
+	normalized := strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(name))
+	for _, preset := range classifiers {
+		set, _ := presets.Get(preset)
+		for _, rule := range set.Rules {
+			if rules.Matches(rule, normalized) {
+				return preset, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Propose returns the spec of an EnvKeyMonitor of the list type built from the keys seen, and the
+// number of keys its rules do not cover. Monitors are proposed in Shadow mode, so accepting a
+// proposal never denies a ConfigMap. Returns nil if no key was found to propose a monitor from
+func Propose(listType configv2.ListType, keys []configv2.ObservedKey) (*configv2.EnvKeyMonitorSpec, int) {
+
+	if listType == configv2.ListTypeAllowlist {
+		return proposeAllowlist(keys)
+	}
+
+	// A Denylist forbids the presets matching suspicious keys, including names not seen yet
+	var proposed []configv2.Preset
+	for _, preset := range classifiers {
+		if slices.ContainsFunc(keys, func(key configv2.ObservedKey) bool { return key.Preset == preset }) {
+			proposed = append(proposed, preset)
+		}
+	}
+	if len(proposed) == 0 {
+		return nil, 0
+	}
+	return &configv2.EnvKeyMonitorSpec{
+		ListType: configv2.ListTypeDenylist,
+		Presets:  proposed,
+		Policy:   configv2.PolicyPermissive,
+		Mode:     configv2.ModeShadow,
+	}, 0
+}
+
+// Propose an Allowlist approving the ConfigMap keys that are not suspicious. Keys sharing a prefix
+// up to the first '_' are approved by a single Prefix rule, unless a suspicious key has the prefix
+func proposeAllowlist(keys []configv2.ObservedKey) (*configv2.EnvKeyMonitorSpec, int) {
+
+	prefixOf := func(name string) string {
+		if i := strings.Index(name, "_"); i > 0 && i < len(name)-1 {
+			return name[:i+1]
+		}
+		return ""
+	}
+
+	approved := map[string][]string{}
+	suspicious := map[string]bool{}
+	for _, key := range keys {
+		switch {
+		case key.Preset != "":
+			suspicious[prefixOf(key.Name)] = true
+		case slices.Contains(key.Sources, configv2.KeySourceConfigMap):
+			approved[prefixOf(key.Name)] = append(approved[prefixOf(key.Name)], key.Name)
+		}
+	}
+
+	// Rules with the number of keys they approve
+	type proposal struct {
+		rule configv2.KeyRule
+		keys int
+	}
+	var proposals []proposal
+	for prefix, names := range approved {
+		if prefix != "" && len(names) >= minPrefixKeys && !suspicious[prefix] {
+			proposals = append(proposals, proposal{
+				rule: configv2.KeyRule{Name: prefix, Match: configv2.MatchPrefix},
+				keys: len(names),
+			})
+			continue
+		}
+		for _, name := range names {
+			proposals = append(proposals, proposal{rule: configv2.KeyRule{Name: name, Match: configv2.MatchExact}, keys: 1})
+		}
+	}
+	if len(proposals) == 0 {
+		return nil, 0
+	}
+	// Keep the rules approving the most keys if there are more than fit into a spec
+	slices.SortFunc(proposals, func(a, b proposal) int {
+		if a.keys != b.keys {
+			return b.keys - a.keys
+		}
+		return strings.Compare(a.rule.Name, b.rule.Name)
+	})
+
+	spec := &configv2.EnvKeyMonitorSpec{
+		ListType: configv2.ListTypeAllowlist,
+		Policy:   configv2.PolicyPermissive,
+		Mode:     configv2.ModeShadow,
+	}
+	uncovered := 0
+	for i, p := range proposals {
+		if i >= maxRules {
+			uncovered += p.keys
+			continue
+		}
+		spec.Rules = append(spec.Rules, p.rule)
+	}
+	slices.SortFunc(spec.Rules, func(a, b configv2.KeyRule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return spec, uncovered
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package baseline
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("Baseline", func() {
+	var (
+		configmaps []corev1.ConfigMap
+		pods       []corev1.Pod
+		now        metav1.Time
+	)
+
+	BeforeEach(func() {
+		configmaps = []corev1.ConfigMap{{
+			Data:       map[string]string{"APP_NAME": "shop", "DB_PASSWORD": "secret"},
+			BinaryData: map[string][]byte{"cert.pem": []byte("value")},
+		}}
+		pods = []corev1.Pod{{Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Env: []corev1.EnvVar{{Name: "APP_NAME"}}}},
+			Containers:     []corev1.Container{{Env: []corev1.EnvVar{{Name: "github-token"}}}},
+		}}}
+		now = metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	})
+
+	It("Should inventory configmap keys and environment variable names", func() {
+		Expect(Inventory(configmaps, pods)).To(Equal(map[string][]configv2.KeySource{
+			"APP_NAME":     {configv2.KeySourceConfigMap, configv2.KeySourceWorkload},
+			"DB_PASSWORD":  {configv2.KeySourceConfigMap},
+			"cert.pem":     {configv2.KeySourceConfigMap},
+			"github-token": {configv2.KeySourceWorkload},
+		}))
+	})
+
+	It("Should classify suspicious keys by the rules of the presets", func() {
+		for name, preset := range map[string]configv2.Preset{
+			"AWS_SECRET_ACCESS_KEY": configv2.PresetCloudCredentials,
+			"orders-db-password":    configv2.PresetDatabase,
+			"github.token":          configv2.PresetGenericSecrets,
+		} {
+			classified, ok := Classify(name)
+			Expect(ok).To(BeTrue(), name)
+			Expect(classified).To(Equal(preset), name)
+		}
+		_, ok := Classify("LOG_LEVEL")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Should keep keys seen before and record when keys were seen", func() {
+		keys, dropped := Merge(nil, Inventory(configmaps, pods), now)
+		Expect(dropped).To(BeZero())
+		Expect(keys).To(HaveLen(4))
+		Expect(keys[0]).To(Equal(configv2.ObservedKey{
+			Name:      "APP_NAME",
+			Sources:   []configv2.KeySource{configv2.KeySourceConfigMap, configv2.KeySourceWorkload},
+			FirstSeen: now,
+			LastSeen:  now,
+		}))
+		Expect(keys[1].Preset).To(Equal(configv2.PresetDatabase))
+
+		By("merging a later inventory")
+		later := metav1.NewTime(now.Add(time.Hour))
+		keys, _ = Merge(keys, Inventory([]corev1.ConfigMap{{Data: map[string]string{"LOG_LEVEL": "debug"}}}, nil), later)
+		Expect(keys).To(HaveLen(5))
+		Expect(keys[0].LastSeen).To(Equal(now))
+		Expect(keys[2].Name).To(Equal("LOG_LEVEL"))
+		Expect(keys[2].FirstSeen).To(Equal(later))
+
+		By("dropping new keys once the status is full")
+		inventory := map[string][]configv2.KeySource{}
+		for i := range MaxKeys {
+			inventory[fmt.Sprintf("KEY_%04d", i)] = []configv2.KeySource{configv2.KeySourceConfigMap}
+		}
+		keys, dropped = Merge(keys, inventory, later)
+		Expect(keys).To(HaveLen(MaxKeys))
+		Expect(dropped).To(Equal(5))
+	})
+
+	It("Should propose the presets matching suspicious keys as a Denylist", func() {
+		keys, _ := Merge(nil, Inventory(configmaps, pods), now)
+		Expect(Propose(configv2.ListTypeDenylist, keys)).To(Equal(&configv2.EnvKeyMonitorSpec{
+			ListType: configv2.ListTypeDenylist,
+			Presets:  []configv2.Preset{configv2.PresetDatabase, configv2.PresetGenericSecrets},
+			Policy:   configv2.PolicyPermissive,
+			Mode:     configv2.ModeShadow,
+		}))
+
+		keys, _ = Merge(nil, Inventory([]corev1.ConfigMap{{Data: map[string]string{"LOG_LEVEL": "debug"}}}, nil), now)
+		spec, _ := Propose(configv2.ListTypeDenylist, keys)
+		Expect(spec).To(BeNil())
+	})
+
+	It("Should propose the configmap keys that are not suspicious as an Allowlist", func() {
+		configmaps[0].Data["LOG_LEVEL"] = "debug"
+		configmaps[0].Data["LOG_FORMAT"] = "json"
+		configmaps[0].Data["LOG_OUTPUT"] = "stdout"
+		configmaps[0].Data["DB_HOST"] = "db"
+		configmaps[0].Data["DB_PORT"] = "5432"
+		configmaps[0].Data["DB_NAME"] = "orders"
+		keys, _ := Merge(nil, Inventory(configmaps, pods), now)
+
+		spec, uncovered := Propose(configv2.ListTypeAllowlist, keys)
+		Expect(uncovered).To(BeZero())
+		Expect(spec.ListType).To(Equal(configv2.ListTypeAllowlist))
+		Expect(spec.Mode).To(Equal(configv2.ModeShadow))
+		Expect(spec.Rules).To(Equal([]configv2.KeyRule{
+			{Name: "APP_NAME", Match: configv2.MatchExact},
+			{Name: "DB_HOST", Match: configv2.MatchExact},
+			{Name: "DB_NAME", Match: configv2.MatchExact},
+			{Name: "DB_PORT", Match: configv2.MatchExact},
+			{Name: "LOG_", Match: configv2.MatchPrefix},
+			{Name: "cert.pem", Match: configv2.MatchExact},
+		}))
+
+		By("reporting keys not covered by the 25 rules approving the most keys")
+		for i := range 30 {
+			configmaps[0].Data[fmt.Sprintf("KEY%02d", i)] = "value"
+		}
+		keys, _ = Merge(nil, Inventory(configmaps, pods), now)
+		spec, uncovered = Propose(configv2.ListTypeAllowlist, keys)
+		Expect(spec.Rules).To(HaveLen(25))
+		Expect(uncovered).To(Equal(11))
+
+		// Rules approving the most keys are kept
+		Expect(spec.Rules).To(ContainElement(configv2.KeyRule{Name: "LOG_", Match: configv2.MatchPrefix}))
+	})
+})
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/baseline"
+)
+
+const (
+	// Learning period of EnvKeyBaseline objects created without the defaults of the CRD
+	defaultBaselineDuration = 7 * 24 * time.Hour
+	// Time between inventories of EnvKeyBaseline objects created without the defaults of the CRD
+	defaultBaselineInterval = 10 * time.Minute
+)
+
+// EnvKeyBaselineReconciler reconciles a EnvKeyBaseline object
+type EnvKeyBaselineReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// APIReader lists Pods without caching them, Pods are only read once per interval
+	APIReader client.Reader
+}
+
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeybaselines,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeybaselines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeybaselines/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// While an EnvKeyBaseline is learning, the keys of all configmaps and the environment variable
+// names of all Pods in its namespace are inventoried every interval. Keys seen during the
+// learning period are recorded in '.status.keys', suspicious keys are classified by the rules of
+// the presets, and an EnvKeyMonitor is proposed in '.status.proposal' for a human to accept.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+func (r *EnvKeyBaselineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var envKeyBaseline configv2.EnvKeyBaseline
+	if err := r.Get(ctx, req.NamespacedName, &envKeyBaseline); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	duration := envKeyBaseline.Spec.Duration.Duration
+	if duration <= 0 {
+		duration = defaultBaselineDuration
+	}
+	interval := envKeyBaseline.Spec.Interval.Duration
+	if interval <= 0 {
+		interval = defaultBaselineInterval
+	}
+	now := metav1.Now()
+	end := envKeyBaseline.GetCreationTimestamp().Add(duration)
+
+	// Check if learning completed, the baseline is kept as it is unless the spec changed since
+	// and learning ends in the future, e.g. after the duration was extended
+	learning := meta.FindStatusCondition(envKeyBaseline.Status.Conditions, "Learning")
+	if envKeyBaseline.Status.CompletionTime != nil {
+		if (learning != nil && learning.ObservedGeneration == envKeyBaseline.GetGeneration()) || !now.Time.Before(end) {
+			return ctrl.Result{}, nil
+		}
+		log.Info(
+			"Learning of EnvKeyBaseline resumed",
+			"name",
+			envKeyBaseline.GetName(),
+			"namespace",
+			envKeyBaseline.GetNamespace(),
+			"until",
+			end.UTC().Format(time.RFC3339),
+		)
+		envKeyBaseline.Status.CompletionTime = nil
+	}
+
+	// Check if the namespace was inventoried less than an interval ago for the current spec,
+	// reconciles caused by restarts or resyncs wait for the next interval
+	if last := envKeyBaseline.Status.LastInventoryTime; last != nil && learning != nil &&
+		learning.ObservedGeneration == envKeyBaseline.GetGeneration() {
+		if next := last.Add(interval); now.Time.Before(next) && now.Time.Before(end) {
+			return ctrl.Result{RequeueAfter: min(next.Sub(now.Time), end.Sub(now.Time))}, nil
+		}
+	}
+
+	// Inventory the namespace
+	var configmapList corev1.ConfigMapList
+	if err := r.List(ctx, &configmapList, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "Cannot list configmaps in namespace", "namespace", req.Namespace)
+		return ctrl.Result{}, err
+	}
+	var podList corev1.PodList
+	if err := r.APIReader.List(ctx, &podList, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "Cannot list pods in namespace", "namespace", req.Namespace)
+		return ctrl.Result{}, err
+	}
+	inventory := baseline.Inventory(configmapList.Items, podList.Items)
+
+	status := &envKeyBaseline.Status
+	keys, dropped := baseline.Merge(status.Keys, inventory, now)
+	status.Keys = keys
+	status.KeyCount = int32(len(keys))
+	status.SuspiciousKeyCount = 0
+	for _, key := range keys {
+		if key.Preset != "" {
+			status.SuspiciousKeyCount++
+		}
+	}
+	status.LastInventoryTime = &now
+
+	listType := envKeyBaseline.Spec.ListType
+	if listType == "" {
+		listType = configv2.ListTypeDenylist
+	}
+	proposal, uncovered := baseline.Propose(listType, keys)
+	status.Proposal = proposal
+
+	condition := metav1.Condition{
+		Type:               "Learning",
+		Status:             metav1.ConditionTrue,
+		Reason:             "Learning",
+		ObservedGeneration: envKeyBaseline.GetGeneration(),
+		Message: fmt.Sprintf(
+			"Found %d keys, %d of them suspicious, learning until %s",
+			status.KeyCount,
+			status.SuspiciousKeyCount,
+			end.UTC().Format(time.RFC3339),
+		),
+	}
+	if !now.Time.Before(end) {
+		status.CompletionTime = &now
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Completed"
+		condition.Message = fmt.Sprintf(
+			"Found %d keys, %d of them suspicious",
+			status.KeyCount,
+			status.SuspiciousKeyCount,
+		)
+	}
+	if dropped > 0 {
+		condition.Message += fmt.Sprintf(", %d keys were not recorded since at most %d keys are kept", dropped, baseline.MaxKeys)
+	}
+	if uncovered > 0 {
+		condition.Message += fmt.Sprintf(", %d keys do not fit into the rules of the proposal", uncovered)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	if err := r.Status().Update(ctx, &envKeyBaseline); err != nil {
+		log.Error(err, "Cannot update status of EnvKeyBaseline")
+		return ctrl.Result{}, err
+	}
+
+	if status.CompletionTime != nil {
+		log.Info(
+			"Learning of EnvKeyBaseline completed",
+			"name",
+			envKeyBaseline.GetName(),
+			"namespace",
+			envKeyBaseline.GetNamespace(),
+			"keys",
+			status.KeyCount,
+		)
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: min(interval, end.Sub(now.Time))}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status writes of an inventory do not change the generation of the EnvKeyBaseline, so they do
+// not trigger another inventory, the next one is requeued after the interval
+func (r *EnvKeyBaselineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&configv2.EnvKeyBaseline{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("envkeybaseline").
+		Complete(r)
+}
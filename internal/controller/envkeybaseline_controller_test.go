@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// podListCounter counts the Pod lists of a reader
+type podListCounter struct {
+	client.Reader
+	lists atomic.Int32
+}
+
+func (r *podListCounter) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*corev1.PodList); ok {
+		r.lists.Add(1)
+	}
+	return r.Reader.List(ctx, list, opts...)
+}
+
+var _ = Describe("EnvKeyBaseline Controller", func() {
+	var (
+		envKeyBaseline *configv2.EnvKeyBaseline
+		configmap      *corev1.ConfigMap
+		pod            *corev1.Pod
+		podReader      *podListCounter
+		reconciler     *EnvKeyBaselineReconciler
+	)
+	key := types.NamespacedName{Name: "legacy", Namespace: "default"}
+
+	BeforeEach(func() {
+		configmap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline-configmap", Namespace: "default"},
+			Data:       map[string]string{"APP_NAME": "shop", "DB_PASSWORD": "secret"},
+		}
+		Expect(k8sClient.Create(ctx, configmap)).To(Succeed())
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "baseline-pod", Namespace: "default"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "app",
+				Image: "shop:latest",
+				Env:   []corev1.EnvVar{{Name: "GITHUB_TOKEN", Value: "ghp_0123456789"}},
+			}}},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+		envKeyBaseline = &configv2.EnvKeyBaseline{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace, CreationTimestamp: metav1.Now()},
+			Spec: configv2.EnvKeyBaselineSpec{
+				Duration: metav1.Duration{Duration: time.Hour},
+				Interval: metav1.Duration{Duration: defaultBaselineInterval},
+				ListType: configv2.ListTypeDenylist,
+			},
+		}
+		podReader = &podListCounter{Reader: k8sClient}
+		reconciler = &EnvKeyBaselineReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), APIReader: podReader}
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, envKeyBaseline)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, configmap)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+	})
+
+	It("should inventory the namespace and propose a monitor while learning", func() {
+		Expect(k8sClient.Create(ctx, envKeyBaseline)).To(Succeed())
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(defaultBaselineInterval))
+
+		Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+		status := envKeyBaseline.Status
+		Expect(status.KeyCount).To(Equal(int32(3)))
+		Expect(status.SuspiciousKeyCount).To(Equal(int32(2)))
+		Expect(status.Keys[1].Name).To(Equal("DB_PASSWORD"))
+		Expect(status.Keys[1].Preset).To(Equal(configv2.PresetDatabase))
+		Expect(status.Keys[2].Sources).To(Equal([]configv2.KeySource{configv2.KeySourceWorkload}))
+		Expect(status.Proposal).NotTo(BeNil())
+		Expect(status.Proposal.Presets).To(Equal([]configv2.Preset{configv2.PresetDatabase, configv2.PresetGenericSecrets}))
+		Expect(status.Proposal.Mode).To(Equal(configv2.ModeShadow))
+		Expect(meta.IsStatusConditionTrue(status.Conditions, "Learning")).To(BeTrue())
+		Expect(status.CompletionTime).To(BeNil())
+	})
+
+	It("should only inventory the namespace once per interval", func() {
+		Expect(k8sClient.Create(ctx, envKeyBaseline)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+		lastInventoryTime := envKeyBaseline.Status.LastInventoryTime
+
+		By("reconciling again before the interval passed")
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(result.RequeueAfter).To(BeNumerically("<=", defaultBaselineInterval))
+		Expect(podReader.lists.Load()).To(Equal(int32(1)))
+		Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+		Expect(envKeyBaseline.Status.LastInventoryTime).To(Equal(lastInventoryTime))
+	})
+
+	It("should inventory once per interval when running in a manager", func() {
+		Expect(k8sClient.Create(ctx, envKeyBaseline)).To(Succeed())
+		stop := startManager(func(mgr ctrl.Manager) error {
+			reconciler.Client = mgr.GetClient()
+			return reconciler.SetupWithManager(mgr)
+		})
+		defer stop()
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+			g.Expect(envKeyBaseline.Status.LastInventoryTime).NotTo(BeNil())
+		}, 10*time.Second).Should(Succeed())
+
+		// The status write of an inventory must not trigger the next one
+		Consistently(podReader.lists.Load, 3*time.Second).Should(Equal(int32(1)))
+	})
+
+	It("should keep the baseline once learning completed", func() {
+		envKeyBaseline.Spec.Duration = metav1.Duration{Duration: time.Nanosecond}
+		envKeyBaseline.Spec.ListType = configv2.ListTypeAllowlist
+		Expect(k8sClient.Create(ctx, envKeyBaseline)).To(Succeed())
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+		Expect(envKeyBaseline.Status.CompletionTime).NotTo(BeNil())
+		Expect(meta.FindStatusCondition(envKeyBaseline.Status.Conditions, "Learning").Reason).To(Equal("Completed"))
+		Expect(envKeyBaseline.Status.Proposal.Rules).To(Equal([]configv2.KeyRule{{Name: "APP_NAME", Match: configv2.MatchExact}}))
+
+		By("ignoring keys added after learning completed")
+		configmap.Data["LOG_LEVEL"] = "debug"
+		Expect(k8sClient.Update(ctx, configmap)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+		Expect(envKeyBaseline.Status.KeyCount).To(Equal(int32(3)))
+
+		By("ignoring spec changes that do not extend learning")
+		envKeyBaseline.Spec.Interval = metav1.Duration{Duration: time.Minute}
+		envKeyBaseline.Generation++
+		Expect(k8sClient.Update(ctx, envKeyBaseline)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+		Expect(envKeyBaseline.Status.CompletionTime).NotTo(BeNil())
+		Expect(envKeyBaseline.Status.KeyCount).To(Equal(int32(3)))
+
+		By("resuming learning once the duration is extended")
+		envKeyBaseline.Spec.Duration = metav1.Duration{Duration: time.Hour}
+		envKeyBaseline.Generation++
+		Expect(k8sClient.Update(ctx, envKeyBaseline)).To(Succeed())
+		result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+		Expect(k8sClient.Get(ctx, key, envKeyBaseline)).To(Succeed())
+		Expect(envKeyBaseline.Status.CompletionTime).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(envKeyBaseline.Status.Conditions, "Learning")).To(BeTrue())
+		Expect(envKeyBaseline.Status.KeyCount).To(Equal(int32(4)))
+	})
+})
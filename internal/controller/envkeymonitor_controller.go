@@ -18,46 +18,492 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	configv1 "github.com/Nivesh00/config-keys-operator.git/api/v1"
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/importer"
+	"github.com/Nivesh00/config-keys-operator.git/internal/keysets"
+	"github.com/Nivesh00/config-keys-operator.git/internal/metrics"
+	"github.com/Nivesh00/config-keys-operator.git/internal/presets"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
 )
 
+// CoverageFinalizer delays the deletion of an EnvKeyMonitor until the keys that are no longer
+// monitored in its namespace have been recorded
+const CoverageFinalizer = "config.core.nvsh-ram.io/coverage"
+
+// Number of deletions recorded in .status.unmonitoredKeys of each EnvKeyMonitor
+const maxUnmonitoredKeysRecords = 10
+
 // EnvKeyMonitorReconciler reconciles a EnvKeyMonitor object
 type EnvKeyMonitorReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeymonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeymonitors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeymonitors/finalizers,verbs=update
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeysets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the EnvKeyMonitor object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
+// EnvKeyMonitor objects in a namespace may hold the same rules, the reconciler
+// reports such rules in '.status.sharedKeys' without changing the spec.
+// When an object is deleted, the keys no longer monitored in the namespace are
+// recorded on the remaining objects before its finalizer is removed.
+// The violations found in the configmaps of the namespace are tracked from
+// opening to resolution in '.status.violations'. For scheduled enforcement,
+// the Enforcing condition is updated when enforcement starts.
+// Violations include the rules of presets, of the referenced EnvKeySet objects and
+// the rules imported from the rule source, objects are reconciled again when a
+// referenced EnvKeySet changes. The preset versions evaluated are listed in
+// '.status.presets', untranslated rules of the rule source in '.status.ruleSource'.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
 func (r *EnvKeyMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = logf.FromContext(ctx)
+	log := logf.FromContext(ctx)
+
+	// Get the EnvKeyMonitor, nothing to do if it was deleted
+	var envKeyMonitor configv2.EnvKeyMonitor
+	if err := r.Get(ctx, req.NamespacedName, &envKeyMonitor); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Get list of EnvKeyMonitor in namespace
+	var envKeyMonitorList configv2.EnvKeyMonitorList
+	if err := r.List(ctx, &envKeyMonitorList, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "Cannot list EnvKeyMonitor objects in namespace", "namespace", req.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	// Record keys no longer monitored before the object is gone
+	if !envKeyMonitor.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(&envKeyMonitor, CoverageFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		resolved := envKeyMonitorList.DeepCopy()
+		if err := keysets.Resolve(ctx, r, resolved); err != nil {
+			log.Error(err, "Cannot resolve the rules of EnvKeyMonitor objects in namespace", "namespace", req.Namespace)
+			return ctrl.Result{}, err
+		}
+		if err := r.recordUnmonitoredKeys(ctx, &envKeyMonitor, &envKeyMonitorList, resolved); err != nil {
+			return ctrl.Result{}, err
+		}
+		metrics.OpenViolations.DeleteLabelValues(envKeyMonitor.GetNamespace(), envKeyMonitor.GetName())
+		controllerutil.RemoveFinalizer(&envKeyMonitor, CoverageFinalizer)
+		return ctrl.Result{}, r.Update(ctx, &envKeyMonitor)
+	}
+	if controllerutil.AddFinalizer(&envKeyMonitor, CoverageFinalizer) {
+		if err := r.Update(ctx, &envKeyMonitor); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Get list of configmaps in namespace
+	var configmapList corev1.ConfigMapList
+	if err := r.List(ctx, &configmapList, client.InNamespace(req.Namespace)); err != nil {
+		log.Error(err, "Cannot list configmaps in namespace", "namespace", req.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	// Get the rules of the presets and referenced EnvKeySet objects
+	keySets, err := keysets.List(ctx, r)
+	if err != nil {
+		log.Error(err, "Cannot list EnvKeySet objects")
+		return ctrl.Result{}, err
+	}
+	// Import the rules of the rule source, a source that cannot be imported is reported in the status
+	imported, importErr := importer.Load(ctx, r, &envKeyMonitor)
+	if importErr != nil {
+		log.Info("Cannot import rule source", "error", importErr.Error())
+	}
+	var importedRules []configv2.KeyRule
+	if imported != nil {
+		importedRules = imported.Rules
+	}
+
+	effective := envKeyMonitor.DeepCopy()
+	var missing []string
+	effective.Spec.Rules, missing = keysets.Effective(effective, keySets, importedRules)
+
+	status := envKeyMonitor.Status.DeepCopy()
+	status.EffectiveKeyCount = int32(len(effective.Spec.Rules))
+	status.Presets = presets.Status(&envKeyMonitor)
+	setKeySetsCondition(&envKeyMonitor, status, missing)
+	setRulesImportedCondition(&envKeyMonitor, status, imported, importErr)
+
+	// Report rules also held by other objects, including the rules of their presets, referenced
+	// EnvKeySet objects and rule sources
+	resolved := envKeyMonitorList.DeepCopy()
+	if err := keysets.Resolve(ctx, r, resolved); err != nil {
+		log.Error(err, "Cannot resolve the rules of EnvKeyMonitor objects in namespace", "namespace", req.Namespace)
+		return ctrl.Result{}, err
+	}
+	status.SharedKeys = findSharedKeys(effective, resolved)
+
+	// Track the lifecycle of the violations of this object
+	now := metav1.Now()
+	status.Violations, status.OpenViolationCount = trackViolations(effective, &configmapList, now)
+	status.Violations, status.PendingAdmissions = recordPendingAdmissions(status.Violations, status.PendingAdmissions, now)
+	setViolationsTruncatedCondition(&envKeyMonitor, status)
+	status.MeanTimeToRemediate = meanTimeToRemediate(&envKeyMonitor, status.Violations, &envKeyMonitorList)
+	r.updateMetrics(&envKeyMonitor, status)
+
+	// Report whether scheduled enforcement started, and check again once it should
+	result := ctrl.Result{}
+	if start, ok := setEnforcingCondition(&envKeyMonitor, status, time.Now()); ok {
+		result.RequeueAfter = time.Until(start)
+	}
+
+	if equality.Semantic.DeepEqual(&envKeyMonitor.Status, status) {
+		return result, nil
+	}
+	if meta.IsStatusConditionTrue(status.Conditions, configv2.ConditionEnforcing) &&
+		!meta.IsStatusConditionTrue(envKeyMonitor.Status.Conditions, configv2.ConditionEnforcing) {
+		r.Recorder.Event(&envKeyMonitor, corev1.EventTypeNormal, "EnforcementStarted", "STRICT policy is enforced from now on")
+	}
+	envKeyMonitor.Status = *status
+	if err := r.Status().Update(ctx, &envKeyMonitor); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Info(
+		"Updated status of EnvKeyMonitor",
+		"name",
+		envKeyMonitor.GetName(),
+		"namespace",
+		envKeyMonitor.GetNamespace(),
+		"shared_keys",
+		len(status.SharedKeys),
+		"violations",
+		len(status.Violations),
+	)
+
+	return result, nil
+}
+
+// Set the Enforcing condition of an EnvKeyMonitor with .spec.enforceAfter, or remove it otherwise.
+// Returns the start of enforcement if it is still pending
+func setEnforcingCondition(envKeyMonitor *configv2.EnvKeyMonitor, status *configv2.EnvKeyMonitorStatus, now time.Time) (time.Time, bool) {
+
+	if envKeyMonitor.Spec.EnforceAfter == nil {
+		meta.RemoveStatusCondition(&status.Conditions, configv2.ConditionEnforcing)
+		return time.Time{}, false
+	}
+
+	condition := metav1.Condition{
+		Type:               configv2.ConditionEnforcing,
+		Status:             metav1.ConditionTrue,
+		Reason:             "EnforcementStarted",
+		Message:            "STRICT policy is enforced",
+		ObservedGeneration: envKeyMonitor.GetGeneration(),
+	}
+	start, ok := rules.EnforcementStart(envKeyMonitor)
+	switch {
+	case !ok:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoMaintenanceWindow"
+		condition.Message = rules.Countdown(envKeyMonitor, now)
+	case now.Before(start):
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "EnforcementScheduled"
+		condition.Message = fmt.Sprintf("STRICT policy is enforced from %s", start.UTC().Format(time.RFC3339))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+
+	return start, ok && now.Before(start)
+}
+
+// Set the KeySetsResolved condition of an EnvKeyMonitor with .spec.keySetRefs, or remove it otherwise.
+// missing are the referenced EnvKeySet objects that do not exist
+func setKeySetsCondition(envKeyMonitor *configv2.EnvKeyMonitor, status *configv2.EnvKeyMonitorStatus, missing []string) {
+
+	if len(envKeyMonitor.Spec.KeySetRefs) == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, configv2.ConditionKeySetsResolved)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               configv2.ConditionKeySetsResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             "KeySetsFound",
+		Message:            "All referenced EnvKeySet objects exist",
+		ObservedGeneration: envKeyMonitor.GetGeneration(),
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "KeySetsNotFound"
+		condition.Message = fmt.Sprintf("EnvKeySet objects %s do not exist", strings.Join(missing, ", "))
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// Set the RulesImported condition and .status.ruleSource of an EnvKeyMonitor with .spec.ruleSource,
+// or remove both otherwise
+func setRulesImportedCondition(
+	envKeyMonitor *configv2.EnvKeyMonitor,
+	status *configv2.EnvKeyMonitorStatus,
+	imported *importer.Result,
+	importErr error,
+) {
+
+	if envKeyMonitor.Spec.RuleSource == nil {
+		meta.RemoveStatusCondition(&status.Conditions, configv2.ConditionRulesImported)
+		status.RuleSource = nil
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               configv2.ConditionRulesImported,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ImportFailed",
+		ObservedGeneration: envKeyMonitor.GetGeneration(),
+	}
+	if importErr != nil {
+		condition.Message = importErr.Error()
+		status.RuleSource = nil
+		meta.SetStatusCondition(&status.Conditions, condition)
+		return
+	}
+
+	status.RuleSource = &configv2.RuleSourceStatus{
+		ImportedRules:       int32(len(imported.Rules)),
+		PartiallyTranslated: imported.PartiallyTranslated,
+		Untranslated:        imported.Untranslated,
+	}
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = "RulesImported"
+	condition.Message = fmt.Sprintf("Imported %d rules", len(imported.Rules))
+	if len(imported.Untranslated) > 0 || len(imported.PartiallyTranslated) > 0 {
+		condition.Reason = "RulesPartiallyImported"
+		condition.Message = fmt.Sprintf(
+			"Imported %d rules, %d of them partially, %d rules cannot be translated",
+			len(imported.Rules),
+			len(imported.PartiallyTranslated),
+			len(imported.Untranslated),
+		)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
+
+// Set the ViolationsTruncated condition of an EnvKeyMonitor with more open violations than tracked
+// in .status.violations, or remove it otherwise
+func setViolationsTruncatedCondition(envKeyMonitor *configv2.EnvKeyMonitor, status *configv2.EnvKeyMonitorStatus) {
+
+	if status.OpenViolationCount <= maxOpenViolations {
+		meta.RemoveStatusCondition(&status.Conditions, configv2.ConditionViolationsTruncated)
+		return
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:   configv2.ConditionViolationsTruncated,
+		Status: metav1.ConditionTrue,
+		Reason: "TooManyViolations",
+		Message: fmt.Sprintf(
+			"Tracking %d of %d open violations, further violations are tracked once tracked ones are resolved",
+			maxOpenViolations,
+			status.OpenViolationCount,
+		),
+		ObservedGeneration: envKeyMonitor.GetGeneration(),
+	})
+}
+
+// Set the metrics of an EnvKeyMonitor and its namespace
+func (r *EnvKeyMonitorReconciler) updateMetrics(envKeyMonitor *configv2.EnvKeyMonitor, status *configv2.EnvKeyMonitorStatus) {
+
+	metrics.OpenViolations.WithLabelValues(envKeyMonitor.GetNamespace(), envKeyMonitor.GetName()).Set(
+		float64(status.OpenViolationCount),
+	)
+
+	if status.MeanTimeToRemediate != nil {
+		metrics.MeanTimeToRemediate.WithLabelValues(envKeyMonitor.GetNamespace()).Set(status.MeanTimeToRemediate.Seconds())
+	}
+}
+
+// Emit an event and add a record to the status of the remaining objects in the namespace listing
+// the keys of a deleted EnvKeyMonitor that are not held by any of them. Rules are compared by
+// identity across the effective rules of all objects, resolved holds the list with effective rules
+func (r *EnvKeyMonitorReconciler) recordUnmonitoredKeys(
+	ctx context.Context,
+	envKeyMonitor *configv2.EnvKeyMonitor,
+	envKeyMonitorList *configv2.EnvKeyMonitorList,
+	resolved *configv2.EnvKeyMonitorList,
+) error {
+	log := logf.FromContext(ctx)
+
+	// Rules of an Allowlist object approve keys, deleting it does not leave keys unmonitored
+	if rules.ListTypeOf(envKeyMonitor) == configv2.ListTypeAllowlist {
+		return nil
+	}
+
+	effective := envKeyMonitor
+	for i := range resolved.Items {
+		if resolved.Items[i].GetName() == envKeyMonitor.GetName() {
+			effective = &resolved.Items[i]
+		}
+	}
+	var keys []string
+	for _, rule := range effective.Spec.Rules {
+		if len(findMonitors(envKeyMonitor, resolved, rule)) == 0 && !slices.Contains(keys, rule.Name) {
+			keys = append(keys, rule.Name)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	log.Info(
+		"Keys are no longer monitored after deletion of EnvKeyMonitor",
+		"name",
+		envKeyMonitor.GetName(),
+		"namespace",
+		envKeyMonitor.GetNamespace(),
+		"keys",
+		keys,
+	)
+
+	message := fmt.Sprintf(
+		"Keys %s are no longer monitored in namespace %s after EnvKeyMonitor %s was deleted",
+		strings.Join(keys, ", "),
+		envKeyMonitor.GetNamespace(),
+		envKeyMonitor.GetName(),
+	)
+	r.Recorder.Event(envKeyMonitor, corev1.EventTypeWarning, "KeysUnmonitored", message)
+
+	unmonitored := configv2.UnmonitoredKeys{
+		Monitor:      envKeyMonitor.GetName(),
+		Keys:         keys,
+		DeletionTime: *envKeyMonitor.DeletionTimestamp,
+	}
+	for i := range envKeyMonitorList.Items {
+		item := &envKeyMonitorList.Items[i]
+		if item.GetName() == envKeyMonitor.GetName() || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if slices.ContainsFunc(item.Status.UnmonitoredKeys, func(u configv2.UnmonitoredKeys) bool {
+			return u.Monitor == unmonitored.Monitor && u.DeletionTime.Equal(&unmonitored.DeletionTime)
+		}) {
+			continue
+		}
+
+		item.Status.UnmonitoredKeys = append(item.Status.UnmonitoredKeys, unmonitored)
+		if len(item.Status.UnmonitoredKeys) > maxUnmonitoredKeysRecords {
+			item.Status.UnmonitoredKeys = item.Status.UnmonitoredKeys[len(item.Status.UnmonitoredKeys)-maxUnmonitoredKeysRecords:]
+		}
+		if err := r.Status().Update(ctx, item); err != nil {
+			return err
+		}
+		r.Recorder.Event(item, corev1.EventTypeWarning, "KeysUnmonitored", message)
+	}
+
+	return nil
+}
+
+// Get the effective rules of an EnvKeyMonitor that are also held by other objects in the list of
+// objects with effective rules, in the order of the effective rules. Returns nil if no rule is shared
+func findSharedKeys(effective *configv2.EnvKeyMonitor, resolved *configv2.EnvKeyMonitorList) []configv2.SharedKey {
+
+	var sharedKeys []configv2.SharedKey
+	for _, rule := range effective.Spec.Rules {
+		monitors := findMonitors(effective, resolved, rule)
+		if len(monitors) == 0 {
+			continue
+		}
+		sharedKeys = append(sharedKeys, configv2.SharedKey{
+			Name:     rule.Name,
+			Match:    keysets.Identity(rule).Match,
+			Monitors: monitors,
+		})
+	}
+	return sharedKeys
+}
+
+// Get the sorted names of the other objects in the list holding a rule with the same identity,
+// objects being deleted and objects of another list type are ignored
+func findMonitors(envKeyMonitor *configv2.EnvKeyMonitor, envKeyMonitorList *configv2.EnvKeyMonitorList, rule configv2.KeyRule) []string {
+
+	id := keysets.Identity(rule)
+	var monitors []string
+	for _, item := range envKeyMonitorList.Items {
+		if item.GetName() == envKeyMonitor.GetName() || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if rules.ListTypeOf(&item) != rules.ListTypeOf(envKeyMonitor) {
+			continue
+		}
+		if slices.ContainsFunc(item.Spec.Rules, func(r configv2.KeyRule) bool { return keysets.Identity(r) == id }) {
+			monitors = append(monitors, item.GetName())
+		}
+	}
+	slices.Sort(monitors)
+	return monitors
+}
+
+// Get all EnvKeyMonitor objects in the namespace of an object, so that a change to one object
+// updates the shared keys of the others, and a change to a configmap updates its violations
+func (r *EnvKeyMonitorReconciler) envKeyMonitorsInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+
+	var envKeyMonitorList configv2.EnvKeyMonitorList
+	if err := r.List(ctx, &envKeyMonitorList, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Cannot list EnvKeyMonitor objects in namespace", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, item := range envKeyMonitorList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+	}
+	return requests
+}
+
+// Get all EnvKeyMonitor objects referencing an EnvKeySet, so that a change to the set updates their
+// violations and effective key count
+func (r *EnvKeyMonitorReconciler) envKeyMonitorsReferencingKeySet(ctx context.Context, obj client.Object) []reconcile.Request {
 
-	// TODO(user): your logic here
+	var envKeyMonitorList configv2.EnvKeyMonitorList
+	if err := r.List(ctx, &envKeyMonitorList); err != nil {
+		logf.FromContext(ctx).Error(err, "Cannot list EnvKeyMonitor objects")
+		return nil
+	}
 
-	return ctrl.Result{}, nil
+	var requests []reconcile.Request
+	for _, item := range envKeyMonitorList.Items {
+		if slices.ContainsFunc(item.Spec.KeySetRefs, func(ref configv2.KeySetReference) bool {
+			return ref.Name == obj.GetName()
+		}) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+		}
+	}
+	return requests
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *EnvKeyMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&configv1.EnvKeyMonitor{}).
+		For(&configv2.EnvKeyMonitor{}).
+		Watches(&configv2.EnvKeyMonitor{}, handler.EnqueueRequestsFromMapFunc(r.envKeyMonitorsInNamespace)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.envKeyMonitorsInNamespace)).
+		Watches(&configv2.EnvKeySet{}, handler.EnqueueRequestsFromMapFunc(r.envKeyMonitorsReferencingKeySet)).
 		Named("envkeymonitor").
 		Complete(r)
 }
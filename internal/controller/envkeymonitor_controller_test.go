@@ -18,16 +18,21 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	configv1 "github.com/Nivesh00/config-keys-operator.git/api/v1"
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
 )
 
 var _ = Describe("EnvKeyMonitor Controller", func() {
@@ -40,18 +45,20 @@ var _ = Describe("EnvKeyMonitor Controller", func() {
 			Name:      resourceName,
 			Namespace: "default", // TODO(user):Modify as needed
 		}
-		envkeymonitor := &configv1.EnvKeyMonitor{}
+		envkeymonitor := &configv2.EnvKeyMonitor{}
 
 		BeforeEach(func() {
 			By("creating the custom resource for the Kind EnvKeyMonitor")
 			err := k8sClient.Get(ctx, typeNamespacedName, envkeymonitor)
 			if err != nil && errors.IsNotFound(err) {
-				resource := &configv1.EnvKeyMonitor{
+				resource := &configv2.EnvKeyMonitor{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: configv2.EnvKeyMonitorSpec{
+						Rules: []configv2.KeyRule{{Name: "API_KEY"}},
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -59,18 +66,33 @@ var _ = Describe("EnvKeyMonitor Controller", func() {
 
 		AfterEach(func() {
 			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &configv1.EnvKeyMonitor{}
+			resource := &configv2.EnvKeyMonitor{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance EnvKeyMonitor")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("Reconciling the deleted resource to remove the finalizer")
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &EnvKeyMonitorReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -79,6 +101,311 @@ var _ = Describe("EnvKeyMonitor Controller", func() {
 			Expect(err).NotTo(HaveOccurred())
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.GetFinalizers()).To(ContainElement(CoverageFinalizer))
+		})
+
+		It("should evaluate the rules of referenced EnvKeySet objects", func() {
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.KeySetRefs = []configv2.KeySetReference{{Name: "cloud"}}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, configv2.ConditionKeySetsResolved)).To(BeTrue())
+			Expect(resource.Status.EffectiveKeyCount).To(Equal(int32(1)))
+
+			By("creating the referenced EnvKeySet")
+			keySet := &configv2.EnvKeySet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cloud"},
+				Spec: configv2.EnvKeySetSpec{Rules: []configv2.KeyRule{
+					{Name: "API_KEY", Match: configv2.MatchExact},
+					{Name: "AWS_", Match: configv2.MatchPrefix},
+					{Name: "GCP_", Match: configv2.MatchPrefix},
+				}},
+			}
+			Expect(k8sClient.Create(ctx, keySet)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, keySet)).To(Succeed()) }()
+			Expect(controllerReconciler.envKeyMonitorsReferencingKeySet(ctx, keySet)).To(ConsistOf(
+				reconcile.Request{NamespacedName: typeNamespacedName},
+			))
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, configv2.ConditionKeySetsResolved)).To(BeTrue())
+			Expect(resource.Status.EffectiveKeyCount).To(Equal(int32(3)))
+		})
+
+		It("should list the versions of the presets evaluated", func() {
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Presets = []configv2.Preset{configv2.PresetCloudCredentials}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Presets).To(Equal([]configv2.PresetStatus{
+				{Name: configv2.PresetCloudCredentials, Version: "v1", KeyCount: 10},
+			}))
+			Expect(resource.Status.EffectiveKeyCount).To(Equal(int32(11)))
+		})
+
+		It("should import the rules of the rule source and report untranslated ones", func() {
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.RuleSource = &configv2.RuleSource{
+				ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "appsec-rules"},
+					Key:                  "baseline.json",
+				},
+				Format: configv2.RuleSourceDetectSecrets,
+			}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, configv2.ConditionRulesImported)).To(BeTrue())
+			Expect(resource.Status.RuleSource).To(BeNil())
+
+			By("creating the rule source")
+			configmap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "appsec-rules", Namespace: "default"},
+				Data: map[string]string{
+					"baseline.json": `{"plugins_used": [{"name": "AWSKeyDetector"}, {"name": "HexHighEntropyString"}]}`,
+				},
+			}
+			Expect(k8sClient.Create(ctx, configmap)).To(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, configmap)).To(Succeed()) }()
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, configv2.ConditionRulesImported)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("RulesPartiallyImported"))
+			Expect(resource.Status.RuleSource.ImportedRules).To(Equal(int32(1)))
+			Expect(resource.Status.RuleSource.Untranslated).To(ConsistOf(HaveField("ID", "HexHighEntropyString")))
+			Expect(resource.Status.EffectiveKeyCount).To(Equal(int32(2)))
+		})
+
+		It("should report scheduled enforcement in the Enforcing condition", func() {
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Policy = configv2.PolicyStrict
+			resource.Spec.EnforceAfter = &metav1.Time{Time: time.Now().Add(time.Hour)}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			condition := meta.FindStatusCondition(resource.Status.Conditions, configv2.ConditionEnforcing)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("EnforcementScheduled"))
+
+			By("switching the condition once enforcement started")
+			resource.Spec.EnforceAfter = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, configv2.ConditionEnforcing)).To(BeTrue())
+		})
+
+		It("should record keys that became unmonitored on the remaining objects", func() {
+			By("creating another object holding a different rule")
+			other := &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-resource",
+					Namespace: "default",
+				},
+				Spec: configv2.EnvKeyMonitorSpec{
+					Rules: []configv2.KeyRule{{Name: "DB_PASSWORD"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+			}()
+
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: recorder,
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("deleting the resource")
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(other), other)).To(Succeed())
+			Expect(other.Status.UnmonitoredKeys).To(HaveLen(1))
+			Expect(other.Status.UnmonitoredKeys[0].Monitor).To(Equal(resourceName))
+			Expect(other.Status.UnmonitoredKeys[0].Keys).To(Equal([]string{"API_KEY"}))
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(<-recorder.Events).To(ContainSubstring("KeysUnmonitored"))
+		})
+
+		It("should only record keys not held by the effective rules of the remaining objects", func() {
+			By("creating another object holding a rule of the same name and a preset")
+			other := &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-resource",
+					Namespace: "default",
+				},
+				Spec: configv2.EnvKeyMonitorSpec{
+					Rules:   []configv2.KeyRule{{Name: "API_KEY", Match: configv2.MatchPrefix}},
+					Presets: []configv2.Preset{configv2.PresetCloudCredentials},
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+			}()
+
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Rules = append(resource.Spec.Rules, configv2.KeyRule{Name: "AWS_ACCESS_KEY_ID"})
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("deleting the resource")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(other), other)).To(Succeed())
+			Expect(other.Status.UnmonitoredKeys).To(HaveLen(1))
+			Expect(other.Status.UnmonitoredKeys[0].Keys).To(Equal([]string{"API_KEY"}))
+		})
+
+		It("should report rules shared with other objects in the namespace", func() {
+			By("creating another object holding the same rule")
+			other := &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-resource",
+					Namespace: "default",
+				},
+				Spec: configv2.EnvKeyMonitorSpec{
+					Rules: []configv2.KeyRule{{Name: "API_KEY"}, {Name: "DB_PASSWORD"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+			}()
+
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.SharedKeys).To(Equal([]configv2.SharedKey{
+				{Name: "API_KEY", Match: configv2.MatchExact, Monitors: []string{"other-resource"}},
+			}))
+			Expect(resource.Spec.Rules).To(HaveLen(1), "Expected the spec to be left unchanged")
+		})
+
+		It("should compare the effective rules of all objects by name, match and value regex", func() {
+			By("creating another object holding a rule of the same name and a preset")
+			other := &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-resource",
+					Namespace: "default",
+				},
+				Spec: configv2.EnvKeyMonitorSpec{
+					Rules:   []configv2.KeyRule{{Name: "API_KEY", Match: configv2.MatchPrefix}},
+					Presets: []configv2.Preset{configv2.PresetCloudCredentials},
+				},
+			}
+			Expect(k8sClient.Create(ctx, other)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, other)).To(Succeed())
+			}()
+
+			resource := &configv2.EnvKeyMonitor{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.Rules = append(resource.Spec.Rules, configv2.KeyRule{Name: "AWS_ACCESS_KEY_ID"})
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &EnvKeyMonitorReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.SharedKeys).To(Equal([]configv2.SharedKey{
+				{Name: "AWS_ACCESS_KEY_ID", Match: configv2.MatchExact, Monitors: []string{"other-resource"}},
+			}))
 		})
 	})
 })
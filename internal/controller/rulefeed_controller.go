@@ -0,0 +1,343 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+const (
+	// Time between polls of RuleFeed objects created without the defaults of the CRD
+	defaultFeedInterval = 5 * time.Minute
+	// Maximum size of a feed response
+	maxFeedSize = 4 << 20
+	// Timeout of a single poll
+	feedTimeout = 30 * time.Second
+	// Maximum number of rules of an EnvKeySet
+	maxKeySetRules = 1000
+)
+
+// feedDocument is the JSON document served by a rule feed
+type feedDocument struct {
+	Version string             `json:"version"`
+	Rules   []configv2.KeyRule `json:"rules"`
+}
+
+// errNotModified is returned when the feed responds with 304 Not Modified
+var errNotModified = errors.New("Feed not modified")
+
+// feedError is a failed poll, reason is set as the reason of the Ready condition
+type feedError struct {
+	reason string
+	err    error
+}
+
+func (e *feedError) Error() string {
+	return e.err.Error()
+}
+
+// RuleFeedReconciler reconciles a RuleFeed object
+type RuleFeedReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	HTTPClient *http.Client
+}
+
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=rulefeeds,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=rulefeeds/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=rulefeeds/finalizers,verbs=update
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeysets,verbs=get;list;watch;create;update;patch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+// A RuleFeed is polled every interval, sending the ETag of the last response in
+// If-None-Match. Valid responses are written to the EnvKeySet owned by the feed,
+// which EnvKeyMonitor objects reference in '.spec.keySetRefs'. If a poll fails,
+// the response is not signed correctly or its rules are invalid, the EnvKeySet
+// keeps the last known good rules and the Ready condition reports the failure.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+func (r *RuleFeedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	// Get the RuleFeed, the owned EnvKeySet is garbage collected if it was deleted
+	var ruleFeed configv2.RuleFeed
+	if err := r.Get(ctx, req.NamespacedName, &ruleFeed); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	interval := ruleFeed.Spec.Interval.Duration
+	if interval <= 0 {
+		interval = defaultFeedInterval
+	}
+
+	// Get the EnvKeySet written by the last poll, a set of the same name not owned by the feed is
+	// never overwritten
+	var keySet configv2.EnvKeySet
+	keySetName := keySetNameOf(&ruleFeed)
+	exists := true
+	if err := r.Get(ctx, client.ObjectKey{Name: keySetName}, &keySet); apierrors.IsNotFound(err) {
+		exists = false
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	ruleFeed.Status.LastPollTime = &now
+	condition := metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: ruleFeed.GetGeneration(),
+	}
+
+	// Only ask for changes if the EnvKeySet still holds the rules of the last response and the
+	// spec did not change since, e.g. to a different URL or public key
+	etag := ruleFeed.Status.ETag
+	ready := meta.FindStatusCondition(ruleFeed.Status.Conditions, "Ready")
+	if !exists || ready == nil || ready.ObservedGeneration != ruleFeed.GetGeneration() ||
+		rulesHash(keySet.Spec.Rules) != ruleFeed.Status.RulesHash {
+		etag = ""
+	}
+
+	var document *feedDocument
+	var responseETag string
+	var err error
+	if exists && !metav1.IsControlledBy(&keySet, &ruleFeed) {
+		err = &feedError{
+			reason: "KeySetConflict",
+			err:    fmt.Errorf("EnvKeySet %s exists and is not owned by the RuleFeed", keySetName),
+		}
+	} else {
+		document, responseETag, err = r.poll(ctx, &ruleFeed, etag)
+	}
+
+	switch {
+	case errors.Is(err, errNotModified):
+		err = nil
+		condition.Reason = "NotModified"
+		condition.Message = fmt.Sprintf("Version %s is up to date", ruleFeed.Status.Version)
+	case err == nil:
+		err = r.writeKeySet(ctx, &ruleFeed, &keySet, exists, document)
+		if err == nil {
+			ruleFeed.Status.ETag = responseETag
+			ruleFeed.Status.RulesHash = rulesHash(keySet.Spec.Rules)
+			ruleFeed.Status.Version = document.Version
+			ruleFeed.Status.RuleCount = int32(len(document.Rules))
+			ruleFeed.Status.LastSyncTime = &now
+			condition.Reason = "Synced"
+			condition.Message = fmt.Sprintf("Wrote %d rules of version %s", len(document.Rules), document.Version)
+		}
+	}
+
+	if err != nil {
+		log.Info("Cannot sync RuleFeed, keeping last known good rules", "error", err.Error())
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PollFailed"
+		var feedErr *feedError
+		if errors.As(err, &feedErr) {
+			condition.Reason = feedErr.reason
+		}
+		condition.Message = err.Error()
+		if ruleFeed.Status.Version != "" {
+			condition.Message += fmt.Sprintf(", keeping last known good version %s", ruleFeed.Status.Version)
+		}
+	}
+	meta.SetStatusCondition(&ruleFeed.Status.Conditions, condition)
+
+	if err := r.Status().Update(ctx, &ruleFeed); err != nil {
+		log.Error(err, "Cannot update status of RuleFeed")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// Get the name of the EnvKeySet a RuleFeed writes to
+func keySetNameOf(ruleFeed *configv2.RuleFeed) string {
+
+	if ruleFeed.Spec.KeySetName != "" {
+		return ruleFeed.Spec.KeySetName
+	}
+	return ruleFeed.GetName()
+}
+
+// Get the hash of the rules of an EnvKeySet
+func rulesHash(rules []configv2.KeyRule) string {
+
+	data, _ := json.Marshal(rules)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// Fetch, verify and parse the feed. Returns errNotModified if the feed did not change since the
+// response with the given ETag
+func (r *RuleFeedReconciler) poll(
+	ctx context.Context,
+	ruleFeed *configv2.RuleFeed,
+	etag string,
+) (*feedDocument, string, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, feedTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ruleFeed.Spec.URL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", &feedError{reason: "PollFailed", err: err}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return nil, "", errNotModified
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return nil, "", &feedError{reason: "PollFailed", err: fmt.Errorf("Feed responded with status %s", resp.Status)}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return nil, "", &feedError{reason: "PollFailed", err: err}
+	}
+	if len(body) > maxFeedSize {
+		return nil, "", &feedError{reason: "InvalidFeed", err: fmt.Errorf("Feed exceeds %d bytes", maxFeedSize)}
+	}
+
+	if err := verifyFeed(ruleFeed.Spec.PublicKey, body, resp.Header.Get(configv2.RuleFeedSignatureHeader)); err != nil {
+		return nil, "", &feedError{reason: "SignatureInvalid", err: err}
+	}
+
+	var document feedDocument
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, "", &feedError{reason: "InvalidFeed", err: fmt.Errorf("Cannot parse feed: %v", err)}
+	}
+	if err := validateFeed(&document); err != nil {
+		return nil, "", &feedError{reason: "InvalidFeed", err: err}
+	}
+	return &document, resp.Header.Get("ETag"), nil
+}
+
+// Verify the Ed25519 signature of a feed, feeds without a public key are not signed
+func verifyFeed(publicKey string, body []byte, signature string) error {
+
+	if publicKey == "" {
+		return nil
+	}
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("Public key is not a base64 encoded Ed25519 public key")
+	}
+	if signature == "" {
+		return fmt.Errorf("Feed is not signed, %s header is missing", configv2.RuleFeedSignatureHeader)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !ed25519.Verify(key, body, decoded) {
+		return fmt.Errorf("Signature of feed is invalid")
+	}
+	return nil
+}
+
+// Check if the rules of a feed can be written to an EnvKeySet
+func validateFeed(document *feedDocument) error {
+
+	if len(document.Rules) == 0 || len(document.Rules) > maxKeySetRules {
+		return fmt.Errorf("Feed holds %d rules, an EnvKeySet holds 1 to %d rules", len(document.Rules), maxKeySetRules)
+	}
+	for i, rule := range document.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("Rule %d of feed has no name", i)
+		}
+		if errs := rules.SyntaxErrors(rule); len(errs) > 0 {
+			return fmt.Errorf("Rule %s of feed can never match a ConfigMap key: %s", rule.Name, strings.Join(errs, ", "))
+		}
+		if rule.Match == configv2.MatchRegex {
+			if _, err := regexp.Compile(rule.Name); err != nil {
+				return fmt.Errorf("Rule %s of feed is not a valid regular expression: %v", rule.Name, err)
+			}
+		}
+		if rule.ValueRegex != "" {
+			if _, err := regexp.Compile(rule.ValueRegex); err != nil {
+				return fmt.Errorf("Value regex of rule %s of feed is not a valid regular expression: %v", rule.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Write the rules of a feed to the EnvKeySet owned by the RuleFeed
+func (r *RuleFeedReconciler) writeKeySet(
+	ctx context.Context,
+	ruleFeed *configv2.RuleFeed,
+	keySet *configv2.EnvKeySet,
+	exists bool,
+	document *feedDocument,
+) error {
+
+	keySet.Spec.Rules = document.Rules
+	if exists {
+		return r.Update(ctx, keySet)
+	}
+
+	keySet.SetName(keySetNameOf(ruleFeed))
+	if err := controllerutil.SetControllerReference(ruleFeed, keySet, r.Scheme); err != nil {
+		return err
+	}
+	return r.Create(ctx, keySet)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Status writes of a poll do not change the generation of the RuleFeed, so they do not
+// trigger another poll, the next one is requeued after the interval
+func (r *RuleFeedReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&configv2.RuleFeed{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&configv2.EnvKeySet{}).
+		Named("rulefeed").
+		Complete(r)
+}
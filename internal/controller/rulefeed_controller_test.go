@@ -0,0 +1,258 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// feedServer is a local stand-in for a rule feed, serving a signed document with an ETag
+type feedServer struct {
+	sync.Mutex
+	*httptest.Server
+	privateKey ed25519.PrivateKey
+	body       string
+	etag       string
+	status     int
+	requests   []*http.Request
+}
+
+func newFeedServer(privateKey ed25519.PrivateKey) *feedServer {
+
+	feed := &feedServer{privateKey: privateKey, status: http.StatusOK}
+	feed.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		feed.Lock()
+		defer feed.Unlock()
+		feed.requests = append(feed.requests, r)
+		if feed.status != http.StatusOK {
+			w.WriteHeader(feed.status)
+			return
+		}
+		if r.Header.Get("If-None-Match") == feed.etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		signature := ed25519.Sign(feed.privateKey, []byte(feed.body))
+		w.Header().Set(configv2.RuleFeedSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+		w.Header().Set("ETag", feed.etag)
+		_, _ = w.Write([]byte(feed.body))
+	}))
+	return feed
+}
+
+// startManager runs a controller in a manager until the returned function is called
+func startManager(setup func(ctrl.Manager) error) context.CancelFunc {
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:     k8sClient.Scheme(),
+		Metrics:    metricsserver.Options{BindAddress: "0"},
+		Controller: config.Controller{SkipNameValidation: ptr.To(true)},
+	})
+	Expect(err).NotTo(HaveOccurred())
+	Expect(setup(mgr)).To(Succeed())
+
+	mgrCtx, stop := context.WithCancel(ctx)
+	go func() {
+		defer GinkgoRecover()
+		Expect(mgr.Start(mgrCtx)).To(Succeed())
+	}()
+	return stop
+}
+
+func (f *feedServer) serve(body, etag string, status int) {
+	f.Lock()
+	defer f.Unlock()
+	f.body, f.etag, f.status = body, etag, status
+}
+
+var _ = Describe("RuleFeed Controller", func() {
+	var (
+		feed       *feedServer
+		ruleFeed   *configv2.RuleFeed
+		reconciler *RuleFeedReconciler
+		publicKey  ed25519.PublicKey
+	)
+	key := types.NamespacedName{Name: "security-feed"}
+
+	BeforeEach(func() {
+		var privateKey ed25519.PrivateKey
+		var err error
+		publicKey, privateKey, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		feed = newFeedServer(privateKey)
+		feed.serve(`{"version": "2026.1", "rules": [{"name": "API_KEY"}, {"name": "AWS_", "match": "Prefix"}]}`, `"v1"`, http.StatusOK)
+
+		ruleFeed = &configv2.RuleFeed{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec: configv2.RuleFeedSpec{
+				URL:       feed.URL,
+				Interval:  metav1.Duration{Duration: defaultFeedInterval},
+				PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+			},
+		}
+		Expect(k8sClient.Create(ctx, ruleFeed)).To(Succeed())
+		reconciler = &RuleFeedReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), HTTPClient: feed.Client()}
+	})
+
+	AfterEach(func() {
+		feed.Close()
+		Expect(k8sClient.Delete(ctx, ruleFeed)).To(Succeed())
+		keySet := &configv2.EnvKeySet{}
+		if err := k8sClient.Get(ctx, key, keySet); err == nil {
+			Expect(k8sClient.Delete(ctx, keySet)).To(Succeed())
+		}
+	})
+
+	reconcileFeed := func() *configv2.RuleFeed {
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(defaultFeedInterval))
+		Expect(k8sClient.Get(ctx, key, ruleFeed)).To(Succeed())
+		return ruleFeed
+	}
+
+	It("should write the rules of the feed and poll with the ETag of the last response", func() {
+		status := reconcileFeed().Status
+		Expect(status.Version).To(Equal("2026.1"))
+		Expect(status.ETag).To(Equal(`"v1"`))
+		Expect(status.RuleCount).To(Equal(int32(2)))
+		Expect(meta.IsStatusConditionTrue(status.Conditions, "Ready")).To(BeTrue())
+
+		keySet := &configv2.EnvKeySet{}
+		Expect(k8sClient.Get(ctx, key, keySet)).To(Succeed())
+		Expect(keySet.Spec.Rules).To(HaveLen(2))
+		Expect(metav1.IsControlledBy(keySet, ruleFeed)).To(BeTrue())
+
+		By("polling an unchanged feed")
+		status = reconcileFeed().Status
+		Expect(feed.requests[1].Header.Get("If-None-Match")).To(Equal(`"v1"`))
+		Expect(meta.FindStatusCondition(status.Conditions, "Ready").Reason).To(Equal("NotModified"))
+
+		By("picking up a new version")
+		feed.serve(`{"version": "2026.2", "rules": [{"name": "API_KEY"}]}`, `"v2"`, http.StatusOK)
+		Expect(reconcileFeed().Status.Version).To(Equal("2026.2"))
+		Expect(k8sClient.Get(ctx, key, keySet)).To(Succeed())
+		Expect(keySet.Spec.Rules).To(HaveLen(1))
+	})
+
+	It("should write the rules again if the EnvKeySet was changed by hand", func() {
+		reconcileFeed()
+		keySet := &configv2.EnvKeySet{}
+		Expect(k8sClient.Get(ctx, key, keySet)).To(Succeed())
+		keySet.Spec.Rules = []configv2.KeyRule{{Name: "EDITED", Match: configv2.MatchExact}}
+		Expect(k8sClient.Update(ctx, keySet)).To(Succeed())
+
+		// The feed answers 304 Not Modified to the ETag, so it must not be sent
+		status := reconcileFeed().Status
+		Expect(feed.requests[1].Header.Get("If-None-Match")).To(BeEmpty())
+		Expect(meta.FindStatusCondition(status.Conditions, "Ready").Reason).To(Equal("Synced"))
+		Expect(k8sClient.Get(ctx, key, keySet)).To(Succeed())
+		Expect(keySet.Spec.Rules).To(HaveLen(2))
+		Expect(keySet.Spec.Rules[0].Name).To(Equal("API_KEY"))
+
+		By("polling with the ETag once the EnvKeySet holds the rules of the feed again")
+		status = reconcileFeed().Status
+		Expect(feed.requests[2].Header.Get("If-None-Match")).To(Equal(`"v1"`))
+		Expect(meta.FindStatusCondition(status.Conditions, "Ready").Reason).To(Equal("NotModified"))
+	})
+
+	It("should poll once per interval when running in a manager", func() {
+		stop := startManager(reconciler.SetupWithManager)
+		defer stop()
+
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, key, ruleFeed)).To(Succeed())
+			g.Expect(ruleFeed.Status.Version).To(Equal("2026.1"))
+		}, 10*time.Second).Should(Succeed())
+
+		// The status writes of a poll must not trigger the next one, only creating the owned
+		// EnvKeySet may cause a second poll which is answered with 304 Not Modified
+		Consistently(func() int {
+			feed.Lock()
+			defer feed.Unlock()
+			return len(feed.requests)
+		}, 3*time.Second).Should(BeNumerically("<=", 2))
+	})
+
+	It("should keep the last known good rules if a poll fails", func() {
+		reconcileFeed()
+
+		for _, response := range []struct {
+			body   string
+			status int
+		}{
+			{body: "", status: http.StatusServiceUnavailable},
+			{body: `{"version": "broken", "rules": []}`, status: http.StatusOK},
+			{body: `{"version": "broken", "rules": [{"name": "(", "match": "Regex"}]}`, status: http.StatusOK},
+			{body: `{"version": "broken", "rules": [{"name": "API KEY"}]}`, status: http.StatusOK},
+		} {
+			feed.serve(response.body, `"broken"`, response.status)
+			status := reconcileFeed().Status
+			Expect(meta.IsStatusConditionFalse(status.Conditions, "Ready")).To(BeTrue())
+			Expect(meta.FindStatusCondition(status.Conditions, "Ready").Message).To(ContainSubstring("last known good version 2026.1"))
+			Expect(status.Version).To(Equal("2026.1"))
+			Expect(status.ETag).To(Equal(`"v1"`))
+		}
+
+		keySet := &configv2.EnvKeySet{}
+		Expect(k8sClient.Get(ctx, key, keySet)).To(Succeed())
+		Expect(keySet.Spec.Rules).To(HaveLen(2))
+	})
+
+	It("should reject responses without a valid signature", func() {
+		_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		feed.privateKey = otherKey
+
+		status := reconcileFeed().Status
+		Expect(meta.FindStatusCondition(status.Conditions, "Ready").Reason).To(Equal("SignatureInvalid"))
+		Expect(k8sClient.Get(ctx, key, &configv2.EnvKeySet{})).NotTo(Succeed())
+	})
+
+	It("should not overwrite an EnvKeySet it does not own", func() {
+		keySet := &configv2.EnvKeySet{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name},
+			Spec:       configv2.EnvKeySetSpec{Rules: []configv2.KeyRule{{Name: "OWN_RULE"}}},
+		}
+		Expect(k8sClient.Create(ctx, keySet)).To(Succeed())
+
+		status := reconcileFeed().Status
+		Expect(meta.FindStatusCondition(status.Conditions, "Ready").Reason).To(Equal("KeySetConflict"))
+		Expect(feed.requests).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, key, keySet)).To(Succeed())
+		Expect(keySet.Spec.Rules).To(Equal([]configv2.KeyRule{{Name: "OWN_RULE"}}))
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+const (
+	// Number of resolved violations kept in .status.violations of each EnvKeyMonitor
+	maxResolvedViolations = 20
+	// Number of open violations kept in .status.violations of each EnvKeyMonitor, so the status
+	// stays below the size limit of objects
+	maxOpenViolations = 100
+	// Time a pending admission waits for its violation to be opened, e.g. until the configmap is
+	// in the cache. Admissions of configmaps rejected by another webhook are dropped afterwards
+	pendingAdmissionTTL = 5 * time.Minute
+)
+
+// Update the violation records of an EnvKeyMonitor with the violations currently found in the
+// configmaps of its namespace. New violations are opened, violations listed in the
+// AcknowledgedKeysAnnotation of their configmap are acknowledged and violations no longer found
+// are resolved, by edit if the configmap still exists and by delete otherwise.
+// Open violations are returned first, followed by the most recently resolved ones. At most
+// maxOpenViolations open violations are tracked, violations already tracked are kept first. The
+// number of all open violations is returned as well
+func trackViolations(
+	envKeyMonitor *configv2.EnvKeyMonitor,
+	configmapList *corev1.ConfigMapList,
+	now metav1.Time,
+) ([]configv2.ViolationRecord, int32) {
+
+	// Get current violations of this object only, other objects track their own
+	envKeyMonitorList := &configv2.EnvKeyMonitorList{Items: []configv2.EnvKeyMonitor{*envKeyMonitor}}
+	configmaps := map[string]*corev1.ConfigMap{}
+	current := map[string]configv2.ViolationRecord{}
+	for i := range configmapList.Items {
+		configmap := &configmapList.Items[i]
+		configmaps[configmap.GetName()] = configmap
+		for _, violation := range rules.Find(envKeyMonitorList, configmap) {
+			record := configv2.ViolationRecord{
+				ConfigMap: violation.ConfigMap,
+				Key:       violation.Key,
+				Rule:      violation.Rule.Name,
+				Kind:      violation.Kind,
+				Severity:  violation.Rule.Severity,
+				State:     configv2.ViolationOpen,
+				OpenedAt:  now,
+			}
+			current[recordID(record)] = record
+		}
+	}
+
+	var open, resolved []configv2.ViolationRecord
+	for _, record := range envKeyMonitor.Status.Violations {
+		if record.ResolvedAt != nil {
+			resolved = append(resolved, record)
+			continue
+		}
+
+		// Violation still present, the severity of the rule may have changed
+		if currentRecord, ok := current[recordID(record)]; ok {
+			delete(current, recordID(record))
+			record.Severity = currentRecord.Severity
+			open = append(open, acknowledge(record, configmaps[record.ConfigMap], now))
+			continue
+		}
+
+		// Violation resolved since the last reconciliation
+		record.State = configv2.ViolationResolvedByEdit
+		if _, ok := configmaps[record.ConfigMap]; !ok {
+			record.State = configv2.ViolationResolvedByDelete
+		}
+		record.ResolvedAt = &now
+		resolved = append(resolved, record)
+	}
+	openCount := int32(len(open) + len(current))
+	for _, id := range slices.Sorted(maps.Keys(current)) {
+		if len(open) >= maxOpenViolations {
+			break
+		}
+		open = append(open, acknowledge(current[id], configmaps[current[id].ConfigMap], now))
+	}
+	if len(open) > maxOpenViolations {
+		open = open[:maxOpenViolations]
+	}
+
+	slices.SortFunc(open, func(a, b configv2.ViolationRecord) int {
+		return strings.Compare(recordID(a), recordID(b))
+	})
+	slices.SortStableFunc(resolved, func(a, b configv2.ViolationRecord) int {
+		return a.ResolvedAt.Compare(b.ResolvedAt.Time)
+	})
+	if len(resolved) > maxResolvedViolations {
+		resolved = resolved[len(resolved)-maxResolvedViolations:]
+	}
+
+	return append(open, resolved...), openCount
+}
+
+// Record the users of pending admissions on the open violations they admitted. The violation is
+// considered opened at the time of the admission. Returns the violations and the admissions still
+// waiting for their violation, admissions older than pendingAdmissionTTL are dropped
+func recordPendingAdmissions(
+	violations []configv2.ViolationRecord,
+	pendingAdmissions []configv2.PendingAdmission,
+	now metav1.Time,
+) ([]configv2.ViolationRecord, []configv2.PendingAdmission) {
+
+	var remaining []configv2.PendingAdmission
+	for _, pending := range pendingAdmissions {
+		id := recordID(configv2.ViolationRecord{
+			ConfigMap: pending.ConfigMap,
+			Key:       pending.Key,
+			Kind:      pending.Kind,
+			Rule:      pending.Rule,
+		})
+		index := slices.IndexFunc(violations, func(record configv2.ViolationRecord) bool {
+			return record.ResolvedAt == nil && recordID(record) == id
+		})
+		if index >= 0 {
+			violations[index].AdmittedBy = &pending.AdmittedBy
+			if pending.Time.Before(&violations[index].OpenedAt) {
+				violations[index].OpenedAt = pending.Time
+			}
+			continue
+		}
+		if now.Sub(pending.Time.Time) < pendingAdmissionTTL {
+			remaining = append(remaining, pending)
+		}
+	}
+	return violations, remaining
+}
+
+// Mark an open violation as acknowledged if its key is listed in the AcknowledgedKeysAnnotation
+// of the configmap
+func acknowledge(record configv2.ViolationRecord, configmap *corev1.ConfigMap, now metav1.Time) configv2.ViolationRecord {
+
+	if record.State != configv2.ViolationOpen || configmap == nil {
+		return record
+	}
+	for _, key := range strings.Split(configmap.GetAnnotations()[configv2.AcknowledgedKeysAnnotation], ",") {
+		if strings.TrimSpace(key) == record.Key {
+			record.State = configv2.ViolationAcknowledged
+			record.AcknowledgedAt = &now
+			break
+		}
+	}
+	return record
+}
+
+// Get the mean time between opening and resolving the violations of all objects in the namespace.
+// The records of envKeyMonitor are taken from violations, as its status is not updated yet.
+// Returns nil if no violation was resolved
+func meanTimeToRemediate(
+	envKeyMonitor *configv2.EnvKeyMonitor,
+	violations []configv2.ViolationRecord,
+	envKeyMonitorList *configv2.EnvKeyMonitorList,
+) *metav1.Duration {
+
+	records := slices.Clone(violations)
+	for _, item := range envKeyMonitorList.Items {
+		if item.GetName() == envKeyMonitor.GetName() {
+			continue
+		}
+		records = append(records, item.Status.Violations...)
+	}
+
+	var total time.Duration
+	var count int
+	for _, record := range records {
+		if record.ResolvedAt == nil {
+			continue
+		}
+		total += record.ResolvedAt.Sub(record.OpenedAt.Time)
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	return &metav1.Duration{Duration: (total / time.Duration(count)).Round(time.Second)}
+}
+
+// Get the identity of a violation record
+func recordID(record configv2.ViolationRecord) string {
+	return strings.Join([]string{record.ConfigMap, record.Key, string(record.Kind), record.Rule}, "/")
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("Violation lifecycle", func() {
+	var (
+		envKeyMonitor *configv2.EnvKeyMonitor
+		configmapList *corev1.ConfigMapList
+		opened        metav1.Time
+	)
+
+	BeforeEach(func() {
+		envKeyMonitor = &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules: []configv2.KeyRule{{Name: "API_KEY", Match: configv2.MatchExact}},
+			},
+		}
+		configmapList = &corev1.ConfigMapList{Items: []corev1.ConfigMap{{
+			ObjectMeta: metav1.ObjectMeta{Name: "configmap", Namespace: "default"},
+			Data:       map[string]string{"API_KEY": "value"},
+		}}}
+		opened = metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	})
+
+	It("Should open, acknowledge and resolve violations by edit", func() {
+		envKeyMonitor.Status.Violations, _ = trackViolations(envKeyMonitor, configmapList, opened)
+		Expect(envKeyMonitor.Status.Violations).To(Equal([]configv2.ViolationRecord{{
+			ConfigMap: "configmap",
+			Key:       "API_KEY",
+			Rule:      "API_KEY",
+			State:     configv2.ViolationOpen,
+			OpenedAt:  opened,
+		}}))
+
+		By("acknowledging the key through the configmap annotation")
+		acknowledged := metav1.NewTime(opened.Add(time.Hour))
+		admittedBy := &configv2.Requester{Username: "jane"}
+		envKeyMonitor.Status.Violations[0].AdmittedBy = admittedBy
+		configmapList.Items[0].Annotations = map[string]string{configv2.AcknowledgedKeysAnnotation: "OTHER, API_KEY"}
+		envKeyMonitor.Status.Violations, _ = trackViolations(envKeyMonitor, configmapList, acknowledged)
+		Expect(envKeyMonitor.Status.Violations[0].State).To(Equal(configv2.ViolationAcknowledged))
+		Expect(envKeyMonitor.Status.Violations[0].AcknowledgedAt).To(Equal(&acknowledged))
+		Expect(envKeyMonitor.Status.Violations[0].AdmittedBy).To(Equal(admittedBy))
+
+		By("removing the key from the configmap")
+		resolved := metav1.NewTime(opened.Add(2 * time.Hour))
+		delete(configmapList.Items[0].Data, "API_KEY")
+		envKeyMonitor.Status.Violations, _ = trackViolations(envKeyMonitor, configmapList, resolved)
+		Expect(envKeyMonitor.Status.Violations).To(HaveLen(1))
+		Expect(envKeyMonitor.Status.Violations[0].State).To(Equal(configv2.ViolationResolvedByEdit))
+		Expect(envKeyMonitor.Status.Violations[0].OpenedAt).To(Equal(opened))
+		Expect(envKeyMonitor.Status.Violations[0].ResolvedAt).To(Equal(&resolved))
+	})
+
+	It("Should open violations for existing keys not approved by an Allowlist object", func() {
+		envKeyMonitor.Spec.ListType = configv2.ListTypeAllowlist
+		configmapList.Items[0].Data["LOG_LEVEL"] = "debug"
+		envKeyMonitor.Status.Violations, _ = trackViolations(envKeyMonitor, configmapList, opened)
+		Expect(envKeyMonitor.Status.Violations).To(Equal([]configv2.ViolationRecord{{
+			ConfigMap: "configmap",
+			Key:       "LOG_LEVEL",
+			Kind:      configv2.ViolationKindUnapprovedKey,
+			State:     configv2.ViolationOpen,
+			OpenedAt:  opened,
+		}}))
+	})
+
+	It("Should track a bounded number of open violations and count all of them", func() {
+		envKeyMonitor.Spec.ListType = configv2.ListTypeAllowlist
+		for i := range maxOpenViolations + 10 {
+			configmapList.Items[0].Data[fmt.Sprintf("KEY_%03d", i)] = "value"
+		}
+		violations, openCount := trackViolations(envKeyMonitor, configmapList, opened)
+		Expect(violations).To(HaveLen(maxOpenViolations))
+		Expect(openCount).To(Equal(int32(maxOpenViolations + 10)))
+
+		By("keeping the tracked violations and tracking new ones once others are resolved")
+		envKeyMonitor.Status.Violations = violations
+		delete(configmapList.Items[0].Data, "KEY_000")
+		configmapList.Items[0].Data["A_KEY"] = "value"
+		later := metav1.NewTime(opened.Add(time.Hour))
+		violations, openCount = trackViolations(envKeyMonitor, configmapList, later)
+		Expect(openCount).To(Equal(int32(maxOpenViolations + 10)))
+		Expect(violations).To(HaveLen(maxOpenViolations + 1))
+		Expect(violations[0].Key).To(Equal("A_KEY"))
+		Expect(violations[0].OpenedAt).To(Equal(later))
+		Expect(violations[1].OpenedAt).To(Equal(opened))
+		Expect(violations[maxOpenViolations].State).To(Equal(configv2.ViolationResolvedByEdit))
+	})
+
+	It("Should record pending admissions once their violation is opened", func() {
+		admitted := metav1.NewTime(opened.Add(-time.Second))
+		pendingAdmissions := []configv2.PendingAdmission{
+			{ConfigMap: "configmap", Key: "API_KEY", Rule: "API_KEY", AdmittedBy: configv2.Requester{Username: "jane"}, Time: admitted},
+			{ConfigMap: "rejected", Key: "API_KEY", Rule: "API_KEY", AdmittedBy: configv2.Requester{Username: "joe"}, Time: admitted},
+		}
+		violations, _ := trackViolations(envKeyMonitor, configmapList, opened)
+		violations, pendingAdmissions = recordPendingAdmissions(violations, pendingAdmissions, opened)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].AdmittedBy).To(Equal(&configv2.Requester{Username: "jane"}))
+		Expect(violations[0].OpenedAt).To(Equal(admitted))
+		Expect(pendingAdmissions).To(HaveLen(1))
+
+		By("dropping admissions whose violation is never opened")
+		_, pendingAdmissions = recordPendingAdmissions(violations, pendingAdmissions, metav1.NewTime(opened.Add(pendingAdmissionTTL)))
+		Expect(pendingAdmissions).To(BeEmpty())
+	})
+
+	It("Should resolve violations by delete and compute the mean time to remediate", func() {
+		envKeyMonitor.Status.Violations, _ = trackViolations(envKeyMonitor, configmapList, opened)
+
+		configmapList.Items = nil
+		envKeyMonitor.Status.Violations, _ = trackViolations(envKeyMonitor, configmapList, metav1.NewTime(opened.Add(time.Hour)))
+		Expect(envKeyMonitor.Status.Violations[0].State).To(Equal(configv2.ViolationResolvedByDelete))
+
+		By("including the violations of other objects in the namespace")
+		other := envKeyMonitor.DeepCopy()
+		other.Name = "other"
+		other.Status.Violations[0].ResolvedAt = &metav1.Time{Time: opened.Add(3 * time.Hour)}
+		envKeyMonitorList := &configv2.EnvKeyMonitorList{Items: []configv2.EnvKeyMonitor{*envKeyMonitor, *other}}
+		Expect(meanTimeToRemediate(envKeyMonitor, envKeyMonitor.Status.Violations, envKeyMonitorList)).To(
+			Equal(&metav1.Duration{Duration: 2 * time.Hour}),
+		)
+
+		By("reporting nothing without resolved violations")
+		Expect(meanTimeToRemediate(envKeyMonitor, nil, &configv2.EnvKeyMonitorList{})).To(BeNil())
+	})
+})
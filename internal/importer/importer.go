@@ -0,0 +1,299 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer translates the configuration of secret scanners into EnvKeyMonitor rules.
+// Scanner rules detecting secrets by their value become rules with a valueRegex matching any key,
+// named after the scanner rule, rules detecting secrets by the name they are assigned to become
+// key rules.
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// Number of imported rule sources kept for reuse across admission requests
+const maxCachedImports = 16
+
+// Result holds the rules translated from a rule source and the rules that could not be translated.
+// Rules parts of which are not evaluated are translated and listed in PartiallyTranslated as well
+type Result struct {
+	Rules               []configv2.KeyRule
+	PartiallyTranslated []configv2.UntranslatedRule
+	Untranslated        []configv2.UntranslatedRule
+}
+
+// Get the name of a rule checking values only. The name matches every key and holds the id of the
+// scanner rule, so violations of different imported rules are told apart
+func anyKey(id string) string {
+	return "(?:" + regexp.QuoteMeta(id) + ")?.*"
+}
+
+// Import translates a configuration of the given format
+func Import(format configv2.RuleSourceFormat, data []byte) (*Result, error) {
+
+	switch format {
+	case configv2.RuleSourceGitleaks:
+		return importGitleaks(data)
+	case configv2.RuleSourceDetectSecrets:
+		return importDetectSecrets(data)
+	default:
+		return nil, fmt.Errorf("Unknown rule source format %s", format)
+	}
+}
+
+// gitleaksConfig holds the fields of a gitleaks configuration that are translated
+type gitleaksConfig struct {
+	Rules []gitleaksRule `toml:"rules"`
+}
+
+type gitleaksRule struct {
+	ID          string `toml:"id"`
+	Description string `toml:"description"`
+	Regex       string `toml:"regex"`
+	Path        string `toml:"path"`
+
+	// Fields narrowing the secrets reported by a rule, they are not evaluated
+	Entropy     float64          `toml:"entropy"`
+	SecretGroup int              `toml:"secretGroup"`
+	Keywords    []string         `toml:"keywords"`
+	Allowlist   map[string]any   `toml:"allowlist"`
+	Allowlists  []map[string]any `toml:"allowlists"`
+}
+
+// Get the fields of a gitleaks rule that are not evaluated by the translated rule
+func (r gitleaksRule) ignoredFields() []string {
+
+	var fields []string
+	if r.Entropy != 0 {
+		fields = append(fields, "entropy")
+	}
+	if r.SecretGroup != 0 {
+		fields = append(fields, "secretGroup")
+	}
+	if len(r.Keywords) > 0 {
+		fields = append(fields, "keywords")
+	}
+	if len(r.Allowlist) > 0 || len(r.Allowlists) > 0 {
+		fields = append(fields, "allowlist")
+	}
+	return fields
+}
+
+// Operators gitleaks generates between the name a secret is assigned to and its value
+const gitleaksAssignment = `:{1,3}=`
+
+func importGitleaks(data []byte) (*Result, error) {
+
+	var config gitleaksConfig
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("Cannot parse gitleaks configuration: %v", err)
+	}
+
+	result := &Result{}
+	for i, rule := range config.Rules {
+		id := rule.ID
+		if id == "" {
+			id = fmt.Sprintf("rules[%d]", i)
+		}
+		untranslated := func(reason string) {
+			result.Untranslated = append(result.Untranslated, configv2.UntranslatedRule{ID: id, Reason: reason})
+		}
+
+		switch {
+		case rule.Regex == "" && rule.Path != "":
+			untranslated("Rule only matches file paths, ConfigMap keys have none")
+			continue
+		case rule.Regex == "":
+			untranslated("Rule has no regex")
+			continue
+		case rule.Path != "":
+			untranslated("Rule is limited to file paths, ConfigMap keys have none")
+			continue
+		case strings.Contains(rule.Regex, gitleaksAssignment):
+			untranslated("Rule matches a name assigned a value in a file, ConfigMap keys and values are matched separately")
+			continue
+		}
+		if _, err := regexp.Compile(rule.Regex); err != nil {
+			untranslated(fmt.Sprintf("Regex is not a valid regular expression: %v", err))
+			continue
+		}
+
+		result.Rules = append(result.Rules, configv2.KeyRule{
+			Name:       anyKey(id),
+			Match:      configv2.MatchRegex,
+			ValueRegex: rule.Regex,
+			Message:    message("gitleaks rule", id, rule.Description),
+		})
+		// The translated rule reports every value matching the regex, including values gitleaks
+		// would skip for their entropy, missing keywords or an allowlist
+		if fields := rule.ignoredFields(); len(fields) > 0 {
+			result.PartiallyTranslated = append(result.PartiallyTranslated, configv2.UntranslatedRule{
+				ID:     id,
+				Reason: fmt.Sprintf("Rule fields %s are not evaluated, values are matched by regex only", strings.Join(fields, ", ")),
+			})
+		}
+	}
+	return result, nil
+}
+
+// detectSecretsBaseline holds the fields of a detect-secrets baseline that are translated
+type detectSecretsBaseline struct {
+	PluginsUsed []struct {
+		Name string `json:"name"`
+	} `json:"plugins_used"`
+}
+
+// Rules equivalent to the detect-secrets plugins. Plugins detecting secrets by their entropy
+// have no equivalent
+var detectSecretsPlugins = map[string]configv2.KeyRule{
+	"KeywordDetector": {
+		Name: `(?i)(api_?key|auth_?key|service_?key|account_?key|db_?key|database_?key|priv_?key|` +
+			`private_?key|client_?key|db_?pass|database_?pass|key_?pass|password|passwd|pwd|secret)`,
+		Match: configv2.MatchRegex,
+	},
+	"AWSKeyDetector":          {ValueRegex: `(?:A3T[A-Z0-9]|ABIA|ACCA|AKIA|ASIA)[0-9A-Z]{16}`},
+	"ArtifactoryDetector":     {ValueRegex: `(?:\s|=|:|"|^)AKC[a-zA-Z0-9]{10,}|(?:\s|=|:|"|^)AP[\dABCDEF][a-zA-Z0-9]{8,}`},
+	"AzureStorageKeyDetector": {ValueRegex: `AccountKey=[a-zA-Z0-9+/=]{88}`},
+	"BasicAuthDetector":       {ValueRegex: `://[^:/?#\[\]@!$&'()*+,;=\s]+:[^:/?#\[\]@!$&'()*+,;=\s]+@`},
+	"GitHubTokenDetector":     {ValueRegex: `(?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9_]{36}`},
+	"JwtTokenDetector":        {ValueRegex: `eyJ[A-Za-z0-9_=-]+\.eyJ[A-Za-z0-9_=-]+\.?[A-Za-z0-9_.+/=-]*`},
+	"MailchimpDetector":       {ValueRegex: `[0-9a-z]{32}-us[0-9]{1,2}`},
+	"NpmDetector":             {ValueRegex: `//.+/:_authToken=\s*(?:npm_.+|[A-Fa-f0-9-]{36})`},
+	"PrivateKeyDetector":      {ValueRegex: `BEGIN (?:DSA |EC |OPENSSH |PGP |RSA |SSH2 ENCRYPTED )?PRIVATE KEY`},
+	"SendGridDetector":        {ValueRegex: `SG\.[a-zA-Z0-9_-]{22}\.[a-zA-Z0-9_-]{43}`},
+	"SlackDetector": {
+		ValueRegex: `xox(?:a|b|p|o|s|r)-(?:\d+-)+[a-z0-9]+|https://hooks\.slack\.com/services/T[a-zA-Z0-9_]+/B[a-zA-Z0-9_]+/[a-zA-Z0-9_]+`,
+	},
+	"StripeDetector":    {ValueRegex: `(?:r|s)k_live_[0-9a-zA-Z]{24}`},
+	"TwilioKeyDetector": {ValueRegex: `(?:AC|SK)[a-z0-9]{32}`},
+}
+
+func importDetectSecrets(data []byte) (*Result, error) {
+
+	var baseline detectSecretsBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("Cannot parse detect-secrets baseline: %v", err)
+	}
+
+	result := &Result{}
+	for _, plugin := range baseline.PluginsUsed {
+		rule, ok := detectSecretsPlugins[plugin.Name]
+		switch {
+		case ok:
+		case strings.HasSuffix(plugin.Name, "HighEntropyString"):
+			result.Untranslated = append(result.Untranslated, configv2.UntranslatedRule{
+				ID:     plugin.Name,
+				Reason: "Plugin detects secrets by their entropy, which rules cannot express",
+			})
+			continue
+		default:
+			result.Untranslated = append(result.Untranslated, configv2.UntranslatedRule{
+				ID:     plugin.Name,
+				Reason: "Plugin has no equivalent rule",
+			})
+			continue
+		}
+
+		if rule.Name == "" {
+			rule.Name = anyKey(plugin.Name)
+			rule.Match = configv2.MatchRegex
+		}
+		rule.Message = message("detect-secrets plugin", plugin.Name, "")
+		result.Rules = append(result.Rules, rule)
+	}
+	return result, nil
+}
+
+// Get the message of a translated rule, descriptions are left out if they would be read as a template
+func message(kind, id, description string) string {
+
+	if description == "" || strings.Contains(description, "{{") {
+		return fmt.Sprintf("{{ .Key }} holds a secret detected by %s %s", kind, id)
+	}
+	return fmt.Sprintf("{{ .Key }} holds a secret detected by %s %s: %s", kind, id, description)
+}
+
+// Imports by the fingerprint of the format and configuration they were translated from
+var imports = struct {
+	sync.Mutex
+	cache map[[sha256.Size]byte]*Result
+}{cache: map[[sha256.Size]byte]*Result{}}
+
+// Load reads and imports the rule source of an EnvKeyMonitor. Sources seen recently are not
+// translated again. Returns nil if the EnvKeyMonitor has no rule source
+func Load(ctx context.Context, c client.Reader, envKeyMonitor *configv2.EnvKeyMonitor) (*Result, error) {
+
+	source := envKeyMonitor.Spec.RuleSource
+	if source == nil {
+		return nil, nil
+	}
+
+	var configmap corev1.ConfigMap
+	key := types.NamespacedName{Namespace: envKeyMonitor.GetNamespace(), Name: source.ConfigMapKeyRef.Name}
+	if err := c.Get(ctx, key, &configmap); err != nil {
+		return nil, fmt.Errorf("Cannot get rule source ConfigMap %s: %v", key.String(), err)
+	}
+	data, ok := configmap.BinaryData[source.ConfigMapKeyRef.Key]
+	if value, found := configmap.Data[source.ConfigMapKeyRef.Key]; found {
+		data, ok = []byte(value), true
+	}
+	if !ok {
+		return nil, fmt.Errorf("Rule source ConfigMap %s has no key %s", key.String(), source.ConfigMapKeyRef.Key)
+	}
+
+	fingerprint := sha256.Sum256(append([]byte(source.Format+"\x00"), data...))
+	imports.Lock()
+	result, ok := imports.cache[fingerprint]
+	imports.Unlock()
+	if ok {
+		return clone(result), nil
+	}
+
+	result, err := Import(source.Format, data)
+	if err != nil {
+		return nil, err
+	}
+	imports.Lock()
+	defer imports.Unlock()
+	if len(imports.cache) >= maxCachedImports {
+		clear(imports.cache)
+	}
+	imports.cache[fingerprint] = result
+	return clone(result), nil
+}
+
+// Copy a cached result, so callers may modify it
+func clone(result *Result) *Result {
+	return &Result{
+		Rules:               slices.Clone(result.Rules),
+		PartiallyTranslated: slices.Clone(result.PartiallyTranslated),
+		Untranslated:        slices.Clone(result.Untranslated),
+	}
+}
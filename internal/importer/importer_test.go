@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"regexp"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+const gitleaks = `
+title = "AppSec rules"
+
+[[rules]]
+id = "github-pat"
+description = "GitHub Personal Access Token"
+regex = '''ghp_[0-9a-zA-Z]{36}'''
+keywords = ["ghp_"]
+
+[[rules]]
+id = "generic-api-key"
+regex = '''(?i)[\w.-]{0,50}?(?:key|token)(?:[ \t\w.-]{0,20})[\s'"]{0,3}(?:=|>|:{1,3}=|\|\|:|<=|=>|:|\?=)([\w.=-]{10,150})'''
+entropy = 3.5
+
+[[rules]]
+id = "pkcs12-file"
+path = '''(?i)(?:^|\/)[^\/]+\.p(?:12|fx)$'''
+
+[[rules]]
+id = "lookahead"
+regex = '''secret(?=[0-9])'''
+
+[[rules]]
+id = "gitlab-pat"
+regex = '''glpat-[0-9a-zA-Z_-]{20}'''
+entropy = 3
+secretGroup = 1
+
+[[rules.allowlists]]
+regexes = ['''glpat-example''']
+
+[[rules]]
+id = "slack-webhook"
+regex = '''https://hooks\.slack\.com/services/[A-Za-z0-9+/]{43,46}'''
+`
+
+const detectSecrets = `{
+  "version": "1.5.0",
+  "plugins_used": [
+    {"name": "AWSKeyDetector"},
+    {"name": "KeywordDetector", "keyword_exclude": ""},
+    {"name": "Base64HighEntropyString", "limit": 4.5},
+    {"name": "CustomDetector"}
+  ],
+  "results": {}
+}`
+
+var _ = Describe("Importer", func() {
+	It("Should translate gitleaks rules matching values and report the others", func() {
+		result, err := Import(configv2.RuleSourceGitleaks, []byte(gitleaks))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Rules).To(HaveLen(3))
+		Expect(result.Rules[0]).To(Equal(configv2.KeyRule{
+			Name:       "(?:github-pat)?.*",
+			Match:      configv2.MatchRegex,
+			ValueRegex: "ghp_[0-9a-zA-Z]{36}",
+			Message:    "{{ .Key }} holds a secret detected by gitleaks rule github-pat: GitHub Personal Access Token",
+		}))
+		Expect(result.Rules[1].Name).To(Equal("(?:gitlab-pat)?.*"))
+		Expect(result.Rules[2].Name).To(Equal(`(?:slack-webhook)?.*`))
+
+		// Imported rules match every key
+		for _, rule := range result.Rules {
+			Expect(regexp.MustCompile(rule.Name).MatchString("ANY_KEY")).To(BeTrue())
+		}
+
+		// Rules whose secrets are narrowed by fields that are not evaluated are reported
+		Expect(result.PartiallyTranslated).To(Equal([]configv2.UntranslatedRule{
+			{ID: "github-pat", Reason: "Rule fields keywords are not evaluated, values are matched by regex only"},
+			{
+				ID:     "gitlab-pat",
+				Reason: "Rule fields entropy, secretGroup, allowlist are not evaluated, values are matched by regex only",
+			},
+		}))
+
+		var ids []string
+		for _, untranslated := range result.Untranslated {
+			Expect(untranslated.Reason).NotTo(BeEmpty())
+			ids = append(ids, untranslated.ID)
+		}
+		Expect(ids).To(Equal([]string{"generic-api-key", "pkcs12-file", "lookahead"}))
+	})
+
+	It("Should translate detect-secrets plugins into key and value rules", func() {
+		result, err := Import(configv2.RuleSourceDetectSecrets, []byte(detectSecrets))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Rules).To(HaveLen(2))
+		Expect(result.Rules[0].ValueRegex).To(ContainSubstring("AKIA"))
+		Expect(result.Rules[0].Name).To(Equal("(?:AWSKeyDetector)?.*"))
+		Expect(result.Rules[1].ValueRegex).To(BeEmpty())
+		Expect(result.Rules[1].Name).To(ContainSubstring("password"))
+		Expect(result.Untranslated).To(Equal([]configv2.UntranslatedRule{
+			{ID: "Base64HighEntropyString", Reason: "Plugin detects secrets by their entropy, which rules cannot express"},
+			{ID: "CustomDetector", Reason: "Plugin has no equivalent rule"},
+		}))
+	})
+
+	It("Should reject configurations that cannot be parsed", func() {
+		Expect(Import(configv2.RuleSourceGitleaks, []byte("[[rules"))).Error().To(HaveOccurred())
+		Expect(Import(configv2.RuleSourceDetectSecrets, []byte("{"))).Error().To(HaveOccurred())
+	})
+
+	It("Should load the rule source of an EnvKeyMonitor from a ConfigMap", func() {
+		configmap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "appsec-rules", Namespace: "default"},
+			Data:       map[string]string{"gitleaks.toml": gitleaks},
+		}
+		reader := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(configmap).Build()
+		envKeyMonitor := &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{RuleSource: &configv2.RuleSource{
+				ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "appsec-rules"},
+					Key:                  "gitleaks.toml",
+				},
+				Format: configv2.RuleSourceGitleaks,
+			}},
+		}
+
+		result, err := Load(context.Background(), reader, envKeyMonitor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Rules).To(HaveLen(3))
+
+		// Cached results are copied
+		result.Rules[0].Name = "CHANGED"
+		result, err = Load(context.Background(), reader, envKeyMonitor)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Rules[0].Name).To(Equal("(?:github-pat)?.*"))
+
+		envKeyMonitor.Spec.RuleSource.ConfigMapKeyRef.Key = "missing"
+		Expect(Load(context.Background(), reader, envKeyMonitor)).Error().To(MatchError(ContainSubstring("has no key missing")))
+
+		envKeyMonitor.Spec.RuleSource = nil
+		Expect(Load(context.Background(), reader, envKeyMonitor)).To(BeNil())
+	})
+})
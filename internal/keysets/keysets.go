@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keysets resolves the cluster scoped EnvKeySet objects, the presets and the rule sources
+// referenced by EnvKeyMonitors.
+package keysets
+
+import (
+	"context"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/importer"
+	"github.com/Nivesh00/config-keys-operator.git/internal/presets"
+)
+
+// List returns all EnvKeySet objects by name
+func List(ctx context.Context, c client.Reader) (map[string]*configv2.EnvKeySet, error) {
+
+	var envKeySetList configv2.EnvKeySetList
+	if err := c.List(ctx, &envKeySetList); err != nil {
+		return nil, err
+	}
+	keySets := make(map[string]*configv2.EnvKeySet, len(envKeySetList.Items))
+	for i := range envKeySetList.Items {
+		keySets[envKeySetList.Items[i].GetName()] = &envKeySetList.Items[i]
+	}
+	return keySets, nil
+}
+
+// Effective returns the rules evaluated for an EnvKeyMonitor: its own rules followed by the rules of
+// the presets in .spec.presets, of the EnvKeySet objects in .spec.keySetRefs and the rules imported
+// from .spec.ruleSource, without rules of the same name, match and value regex listed before.
+// The names of referenced EnvKeySet objects that do not exist are returned as missing
+func Effective(
+	envKeyMonitor *configv2.EnvKeyMonitor,
+	keySets map[string]*configv2.EnvKeySet,
+	imported []configv2.KeyRule,
+) (effective []configv2.KeyRule, missing []string) {
+
+	effective = slices.Clone(envKeyMonitor.Spec.Rules)
+	seen := map[configv2.KeyRule]bool{}
+	for _, rule := range effective {
+		seen[Identity(rule)] = true
+	}
+	add := func(rules []configv2.KeyRule) {
+		for _, rule := range rules {
+			id := Identity(rule)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			effective = append(effective, rule)
+		}
+	}
+	for _, name := range envKeyMonitor.Spec.Presets {
+		if set, ok := presets.Get(name); ok {
+			add(set.Rules)
+		}
+	}
+	for _, ref := range envKeyMonitor.Spec.KeySetRefs {
+		keySet, ok := keySets[ref.Name]
+		if !ok {
+			missing = append(missing, ref.Name)
+			continue
+		}
+		add(keySet.Spec.Rules)
+	}
+	add(imported)
+	return effective, missing
+}
+
+// Identity returns the name, match and value regex of a rule, rules without a match are matched
+// exactly. Rules with the same identity match the same keys
+func Identity(rule configv2.KeyRule) configv2.KeyRule {
+
+	if rule.Match == "" {
+		rule.Match = configv2.MatchExact
+	}
+	return configv2.KeyRule{Name: rule.Name, Match: rule.Match, ValueRegex: rule.ValueRegex}
+}
+
+// Resolve replaces the rules of every EnvKeyMonitor in the list with its effective rules.
+// EnvKeySet objects are only listed if an EnvKeyMonitor references one, missing ones are ignored
+// as are rule sources that cannot be imported, the controller reports both in the status
+func Resolve(ctx context.Context, c client.Reader, envKeyMonitorList *configv2.EnvKeyMonitorList) error {
+
+	var keySets map[string]*configv2.EnvKeySet
+	if slices.ContainsFunc(envKeyMonitorList.Items, func(envKeyMonitor configv2.EnvKeyMonitor) bool {
+		return len(envKeyMonitor.Spec.KeySetRefs) > 0
+	}) {
+		var err error
+		if keySets, err = List(ctx, c); err != nil {
+			return err
+		}
+	}
+
+	for i := range envKeyMonitorList.Items {
+		envKeyMonitor := &envKeyMonitorList.Items[i]
+		if len(envKeyMonitor.Spec.KeySetRefs) == 0 && len(envKeyMonitor.Spec.Presets) == 0 && envKeyMonitor.Spec.RuleSource == nil {
+			continue
+		}
+		var imported []configv2.KeyRule
+		if result, err := importer.Load(ctx, c, envKeyMonitor); err == nil && result != nil {
+			imported = result.Rules
+		}
+		envKeyMonitor.Spec.Rules, _ = Effective(envKeyMonitor, keySets, imported)
+	}
+	return nil
+}
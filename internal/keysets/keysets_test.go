@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keysets
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("KeySets", func() {
+	var (
+		envKeyMonitor *configv2.EnvKeyMonitor
+		keySet        *configv2.EnvKeySet
+	)
+
+	BeforeEach(func() {
+		envKeyMonitor = &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules:      []configv2.KeyRule{{Name: "API_KEY"}},
+				KeySetRefs: []configv2.KeySetReference{{Name: "cloud"}, {Name: "missing"}},
+			},
+		}
+		keySet = &configv2.EnvKeySet{
+			ObjectMeta: metav1.ObjectMeta{Name: "cloud"},
+			Spec: configv2.EnvKeySetSpec{Rules: []configv2.KeyRule{
+				{Name: "API_KEY", Match: configv2.MatchExact},
+				{Name: "API_KEY", Match: configv2.MatchPrefix},
+				{Name: "AWS_", Match: configv2.MatchPrefix},
+			}},
+		}
+	})
+
+	It("Should append the rules of referenced sets and report missing ones", func() {
+		effective, missing := Effective(envKeyMonitor, map[string]*configv2.EnvKeySet{"cloud": keySet}, nil)
+		Expect(effective).To(Equal([]configv2.KeyRule{
+			{Name: "API_KEY"},
+			{Name: "API_KEY", Match: configv2.MatchPrefix},
+			{Name: "AWS_", Match: configv2.MatchPrefix},
+		}))
+		Expect(missing).To(Equal([]string{"missing"}))
+	})
+
+	It("Should expand presets before referenced sets", func() {
+		envKeyMonitor.Spec.Presets = []configv2.Preset{configv2.PresetGenericSecrets}
+		effective, _ := Effective(envKeyMonitor, map[string]*configv2.EnvKeySet{"cloud": keySet}, nil)
+		Expect(effective[0]).To(Equal(configv2.KeyRule{Name: "API_KEY"}))
+		Expect(effective).To(ContainElement(configv2.KeyRule{
+			Name:     "_PASSWORD",
+			Match:    configv2.MatchSuffix,
+			Severity: configv2.SeverityHigh,
+		}))
+		Expect(effective[len(effective)-1]).To(Equal(configv2.KeyRule{Name: "AWS_", Match: configv2.MatchPrefix}))
+
+		// API_KEY of the preset is the same rule as the one of the spec
+		Expect(effective).NotTo(ContainElement(configv2.KeyRule{
+			Name:     "API_KEY",
+			Match:    configv2.MatchExact,
+			Severity: configv2.SeverityHigh,
+		}))
+	})
+
+	It("Should resolve presets without listing sets", func() {
+		scheme := runtime.NewScheme()
+		reader := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		envKeyMonitor.Spec.KeySetRefs = nil
+		envKeyMonitor.Spec.Presets = []configv2.Preset{configv2.PresetDatabase}
+		envKeyMonitorList := &configv2.EnvKeyMonitorList{Items: []configv2.EnvKeyMonitor{*envKeyMonitor}}
+		Expect(Resolve(context.Background(), reader, envKeyMonitorList)).To(Succeed())
+		Expect(envKeyMonitorList.Items[0].Spec.Rules).To(ContainElement(HaveField("Name", "DATABASE_URL")))
+	})
+
+	It("Should resolve the rules of a list without changing the original spec", func() {
+		scheme := runtime.NewScheme()
+		Expect(configv2.AddToScheme(scheme)).To(Succeed())
+		reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(keySet).Build()
+
+		rules := envKeyMonitor.Spec.Rules
+		envKeyMonitorList := &configv2.EnvKeyMonitorList{Items: []configv2.EnvKeyMonitor{*envKeyMonitor}}
+		Expect(Resolve(context.Background(), reader, envKeyMonitorList)).To(Succeed())
+		Expect(envKeyMonitorList.Items[0].Spec.Rules).To(HaveLen(3))
+		Expect(rules).To(HaveLen(1))
+	})
+})
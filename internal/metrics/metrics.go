@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics of the operator. They are registered with the
+// controller-runtime registry and served on the manager's metrics endpoint.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Actions taken on violations during ConfigMap admission
+const (
+	ActionDenied       = "denied"
+	ActionWarned       = "warned"
+	ActionAudited      = "audited"
+	ActionShadowDenied = "shadow_denied"
+	ActionBypassed     = "bypassed"
+)
+
+var (
+	// Violations is the number of forbidden keys found during ConfigMap admission
+	Violations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "envkeymonitor_violations_total",
+			Help: "Number of forbidden keys found during ConfigMap admission",
+		},
+		[]string{"namespace", "monitor", "mode", "severity", "action"},
+	)
+
+	// OpenViolations is the number of open or acknowledged violations of an EnvKeyMonitor
+	OpenViolations = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "envkeymonitor_open_violations",
+			Help: "Number of open or acknowledged violations of an EnvKeyMonitor",
+		},
+		[]string{"namespace", "monitor"},
+	)
+
+	// MeanTimeToRemediate is the mean time between opening and resolving violations in a namespace
+	MeanTimeToRemediate = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "envkeymonitor_mean_time_to_remediate_seconds",
+			Help: "Mean time between opening and resolving violations in a namespace",
+		},
+		[]string{"namespace"},
+	)
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		Violations,
+		OpenViolations,
+		MeanTimeToRemediate,
+	)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// Networks notifications are never sent to unless the operator allows them, in addition to
+// loopback, private, link-local, multicast and unspecified addresses
+var deniedNetworks = []netip.Prefix{
+	// Shared address space, used by some clouds for metadata endpoints
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// ParseAllowedNetworks parses a comma separated list of CIDRs notifications may be sent to even
+// though they are not public, e.g. the service network of the cluster. An empty value allows none
+func ParseAllowedNetworks(value string) ([]netip.Prefix, error) {
+
+	var allowedNetworks []netip.Prefix
+	for cidr := range strings.SplitSeq(value, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid network %s: %v", cidr, err)
+		}
+		allowedNetworks = append(allowedNetworks, prefix.Masked())
+	}
+	return allowedNetworks, nil
+}
+
+// Check if notifications may be sent to an address. Public addresses are allowed, other addresses
+// only if they are in one of the allowed networks
+func isAllowed(addr netip.Addr, allowedNetworks []netip.Prefix) bool {
+
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range allowedNetworks {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range deniedNetworks {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Get an HTTP client only connecting to allowed addresses. Addresses are checked once the name of
+// the target is resolved and for every redirect, names resolving to internal addresses are denied
+// as well
+func newHTTPClient(allowedNetworks []netip.Prefix) *http.Client {
+
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !isAllowed(addrPort.Addr(), allowedNetworks) {
+				return fmt.Errorf(
+					"Address %s is not allowed, notifications are only sent to public addresses and allowed networks",
+					addrPort.Addr(),
+				)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the target on behalf of the operator, bypassing the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: requestTimeout, Transport: transport}
+}
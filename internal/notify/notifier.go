@@ -0,0 +1,370 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify delivers violations to the NotificationTarget objects referenced by EnvKeyMonitors.
+// Violations are queued during admission and sent in batches in the background, so admission
+// latency does not depend on the availability of the targets.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+var notifyLog = logf.Log.WithName("notifier")
+
+const (
+	// Number of notifications waiting to be batched, further notifications are dropped
+	queueSize = 1000
+	// Number of notifications sent in a single request
+	maxBatchSize = 100
+	// How often batches are checked for being due
+	tick = time.Second
+	// Timeout of a single delivery attempt
+	requestTimeout = 10 * time.Second
+	// Number of batches delivered at the same time, further batches wait for a running delivery
+	maxDeliveries = 10
+)
+
+// Defaults for NotificationTarget objects created without the defaults of the CRD
+const (
+	defaultBatchInterval  = 10 * time.Second
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Second
+)
+
+// Notification describes a single violation, key values are never included
+type Notification struct {
+	Monitor   string    `json:"monitor"`
+	Namespace string    `json:"namespace"`
+	ConfigMap string    `json:"configMap"`
+	Key       string    `json:"key"`
+	Rule      string    `json:"rule"`
+	Severity  string    `json:"severity,omitempty"`
+	Action    string    `json:"action"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+}
+
+// Payload is the JSON body sent to a NotificationTarget
+type Payload struct {
+	Target        string         `json:"target"`
+	Namespace     string         `json:"namespace"`
+	Notifications []Notification `json:"notifications"`
+}
+
+type queued struct {
+	target       types.NamespacedName
+	notification Notification
+}
+
+type batch struct {
+	notifications []Notification
+	due           time.Time
+}
+
+// Notifier queues violations and delivers them to NotificationTarget objects in batches.
+// It runs on every replica of the manager, as every replica serves admission requests
+type Notifier struct {
+	client.Client
+	// SecretReader reads the HMAC secrets of targets from their own namespace. It should not be
+	// backed by a cache, so that Secrets are neither watched nor held in memory
+	SecretReader client.Reader
+	HTTPClient   *http.Client
+
+	queue      chan queued
+	deliveries chan struct{}
+}
+
+// NewNotifier returns a Notifier using the client to read NotificationTarget objects and the
+// secret reader to read Secrets. Notifications are only sent to public addresses and addresses in
+// allowedNetworks, so targets cannot reach services of the cluster or metadata endpoints
+func NewNotifier(c client.Client, secretReader client.Reader, allowedNetworks []netip.Prefix) *Notifier {
+	return &Notifier{
+		Client:       c,
+		SecretReader: secretReader,
+		HTTPClient:   newHTTPClient(allowedNetworks),
+		queue:        make(chan queued, queueSize),
+		deliveries:   make(chan struct{}, maxDeliveries),
+	}
+}
+
+// Notify queues a violation for every NotificationTarget referenced by its EnvKeyMonitor.
+// It never blocks, notifications are dropped if the queue is full. Calling Notify on a nil
+// Notifier does nothing
+func (n *Notifier) Notify(violation rules.Violation, action string) {
+
+	if n == nil {
+		return
+	}
+	notification := Notification{
+		Monitor:   violation.Monitor.GetName(),
+		Namespace: violation.Namespace,
+		ConfigMap: violation.ConfigMap,
+		Key:       violation.Key,
+		Rule:      violation.Rule.Name,
+		Severity:  string(violation.Rule.Severity),
+		Action:    action,
+		Message:   violation.String(),
+		Time:      time.Now().UTC(),
+	}
+	for _, ref := range violation.Monitor.Spec.NotificationTargetRefs {
+		target := types.NamespacedName{Namespace: violation.Monitor.GetNamespace(), Name: ref.Name}
+		select {
+		case n.queue <- queued{target: target, notification: notification}:
+		default:
+			notifyLog.Info("Notification queue is full, dropping notification", "target", target.String())
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every replica delivers the
+// violations found by its own webhook server
+func (n *Notifier) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. It collects queued notifications per target and delivers
+// them once the batch interval of the target passed or the batch is full
+func (n *Notifier) Start(ctx context.Context) error {
+
+	batches := map[types.NamespacedName]*batch{}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case item := <-n.queue:
+			b, ok := batches[item.target]
+			if !ok {
+				b = &batch{due: time.Now().Add(n.batchInterval(ctx, item.target))}
+				batches[item.target] = b
+			}
+			b.notifications = append(b.notifications, item.notification)
+			if len(b.notifications) >= maxBatchSize {
+				delete(batches, item.target)
+				n.startDelivery(ctx, item.target, b.notifications)
+			}
+
+		case now := <-ticker.C:
+			for target, b := range batches {
+				if now.Before(b.due) {
+					continue
+				}
+				delete(batches, target)
+				n.startDelivery(ctx, target, b.notifications)
+			}
+		}
+	}
+}
+
+// Deliver notifications in the background once fewer than maxDeliveries deliveries are running.
+// Blocks while all deliveries are running, notifications queued meanwhile are dropped once the
+// queue is full
+func (n *Notifier) startDelivery(ctx context.Context, target types.NamespacedName, notifications []Notification) {
+
+	select {
+	case <-ctx.Done():
+		return
+	case n.deliveries <- struct{}{}:
+	}
+	go func() {
+		defer func() { <-n.deliveries }()
+		n.Deliver(ctx, target, notifications)
+	}()
+}
+
+// Get the batch interval of a target, the default is used if the target cannot be read
+func (n *Notifier) batchInterval(ctx context.Context, target types.NamespacedName) time.Duration {
+
+	var notificationTarget configv2.NotificationTarget
+	if err := n.Get(ctx, target, &notificationTarget); err != nil || notificationTarget.Spec.BatchInterval.Duration <= 0 {
+		return defaultBatchInterval
+	}
+	return notificationTarget.Spec.BatchInterval.Duration
+}
+
+// Deliver sends notifications to a target in a single request, retrying with exponential backoff.
+// The outcome is recorded in the status of the target
+func (n *Notifier) Deliver(ctx context.Context, target types.NamespacedName, notifications []Notification) {
+
+	var notificationTarget configv2.NotificationTarget
+	if err := n.Get(ctx, target, &notificationTarget); err != nil {
+		notifyLog.Error(err, "Cannot get NotificationTarget, dropping notifications", "target", target.String())
+		return
+	}
+
+	body, err := json.Marshal(Payload{
+		Target:        target.Name,
+		Namespace:     target.Namespace,
+		Notifications: notifications,
+	})
+	if err == nil {
+		err = n.send(ctx, &notificationTarget, body)
+	}
+	if err != nil {
+		notifyLog.Error(err, "Cannot deliver notifications", "target", target.String(), "notifications", len(notifications))
+	}
+	n.recordDelivery(ctx, target, len(notifications), err)
+}
+
+// Send a payload to a target until it succeeds or the attempts are exhausted
+func (n *Notifier) send(ctx context.Context, notificationTarget *configv2.NotificationTarget, body []byte) error {
+
+	signature, err := n.sign(ctx, notificationTarget, body)
+	if err != nil {
+		return err
+	}
+
+	maxAttempts := int(notificationTarget.Spec.Retry.MaxAttempts)
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	backoff := notificationTarget.Spec.Retry.InitialBackoff.Duration
+	if backoff <= 0 {
+		backoff = defaultInitialBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = n.post(ctx, notificationTarget.Spec.URL, signature, body)
+		if err == nil || attempt >= maxAttempts {
+			return err
+		}
+		notifyLog.Info(
+			"Delivery failed, retrying",
+			"target",
+			notificationTarget.GetName(),
+			"attempt",
+			attempt,
+			"error",
+			err.Error(),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Send a single POST request, any status other than 2xx is an error
+func (n *Notifier) post(ctx context.Context, url, signature string, body []byte) error {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set(configv2.NotificationSignatureHeader, signature)
+	}
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Target responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+// Get the HMAC-SHA256 signature of a payload, empty if the target has no hmacSecretRef
+func (n *Notifier) sign(ctx context.Context, notificationTarget *configv2.NotificationTarget, body []byte) (string, error) {
+
+	ref := notificationTarget.Spec.HMACSecretRef
+	if ref == nil {
+		return "", nil
+	}
+
+	var secret corev1.Secret
+	key := types.NamespacedName{Namespace: notificationTarget.GetNamespace(), Name: ref.Name}
+	if err := n.SecretReader.Get(ctx, key, &secret); err != nil {
+		return "", fmt.Errorf("Cannot get HMAC secret %s: %v", key.String(), err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("HMAC secret %s has no key %s", key.String(), ref.Key)
+	}
+
+	return "sha256=" + Sign(value, body), nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of a payload
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Record the outcome of a delivery in the status of the target
+func (n *Notifier) recordDelivery(ctx context.Context, target types.NamespacedName, count int, deliveryErr error) {
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var notificationTarget configv2.NotificationTarget
+		if err := n.Get(ctx, target, &notificationTarget); err != nil {
+			return err
+		}
+
+		condition := metav1.Condition{
+			Type:               "Ready",
+			Status:             metav1.ConditionTrue,
+			Reason:             "Delivered",
+			Message:            fmt.Sprintf("Delivered %d notifications", count),
+			ObservedGeneration: notificationTarget.GetGeneration(),
+		}
+		if deliveryErr != nil {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "DeliveryFailed"
+			condition.Message = deliveryErr.Error()
+			notificationTarget.Status.Dropped += int64(count)
+		} else {
+			now := metav1.Now()
+			notificationTarget.Status.LastDeliveryTime = &now
+			notificationTarget.Status.Delivered += int64(count)
+		}
+		meta.SetStatusCondition(&notificationTarget.Status.Conditions, condition)
+
+		return n.Status().Update(ctx, &notificationTarget)
+	})
+	if err != nil {
+		notifyLog.Error(err, "Cannot update status of NotificationTarget", "target", target.String())
+	}
+}
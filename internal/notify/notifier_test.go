@@ -0,0 +1,246 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+// Local stand-in for an HTTP notification receiver, failing the first requests if configured
+type receiver struct {
+	sync.Mutex
+	failures   int
+	requests   int
+	payloads   []Payload
+	signatures []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.requests++
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	var payload Payload
+	Expect(json.Unmarshal(body, &payload)).To(Succeed())
+	r.payloads = append(r.payloads, payload)
+	r.signatures = append(r.signatures, req.Header.Get(configv2.NotificationSignatureHeader))
+}
+
+var _ = Describe("Notifier", func() {
+	var (
+		ctx           context.Context
+		k8sClient     client.Client
+		notifier      *Notifier
+		stub          *receiver
+		server        *httptest.Server
+		target        *configv2.NotificationTarget
+		envKeyMonitor *configv2.EnvKeyMonitor
+		key           types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		stub = &receiver{}
+		server = httptest.NewServer(stub)
+
+		target = &configv2.NotificationTarget{
+			ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "default"},
+			Spec: configv2.NotificationTargetSpec{
+				URL:           server.URL,
+				BatchInterval: metav1.Duration{Duration: time.Millisecond},
+				Retry: configv2.NotificationRetry{
+					MaxAttempts:    3,
+					InitialBackoff: metav1.Duration{Duration: time.Millisecond},
+				},
+			},
+		}
+		key = types.NamespacedName{Namespace: "default", Name: "alerts"}
+		envKeyMonitor = &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				NotificationTargetRefs: []corev1.LocalObjectReference{{Name: "alerts"}},
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(configv2.AddToScheme(scheme)).To(Succeed())
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(target, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "hmac", Namespace: "default"},
+				Data:       map[string][]byte{"key": []byte("secret")},
+			}).
+			WithStatusSubresource(target).
+			Build()
+		notifier = NewNotifier(k8sClient, k8sClient, []netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	violation := func(key string) rules.Violation {
+		return rules.Violation{
+			Monitor:   envKeyMonitor,
+			Rule:      configv2.KeyRule{Name: key, Severity: configv2.SeverityHigh},
+			Field:     "data",
+			Key:       key,
+			ConfigMap: "app",
+			Namespace: "default",
+		}
+	}
+
+	It("Should deliver queued violations in a single batch", func() {
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(notifier.Start(runCtx)).To(Succeed())
+		}()
+
+		notifier.Notify(violation("PASSWORD"), "denied")
+		notifier.Notify(violation("TOKEN"), "warned")
+
+		Eventually(func(g Gomega) {
+			stub.Lock()
+			defer stub.Unlock()
+			g.Expect(stub.payloads).To(HaveLen(1))
+			g.Expect(stub.payloads[0].Target).To(Equal("alerts"))
+			g.Expect(stub.payloads[0].Notifications).To(HaveLen(2))
+			g.Expect(stub.payloads[0].Notifications[0].Key).To(Equal("PASSWORD"))
+			g.Expect(stub.payloads[0].Notifications[0].Action).To(Equal("denied"))
+			g.Expect(stub.payloads[0].Notifications[1].Key).To(Equal("TOKEN"))
+		}, 5*time.Second).Should(Succeed())
+
+		Eventually(func(g Gomega) {
+			var status configv2.NotificationTarget
+			g.Expect(k8sClient.Get(ctx, key, &status)).To(Succeed())
+			g.Expect(status.Status.Delivered).To(Equal(int64(2)))
+			g.Expect(status.Status.LastDeliveryTime).NotTo(BeNil())
+		}).Should(Succeed())
+	})
+
+	It("Should sign the payload with the HMAC secret", func() {
+		target.Spec.HMACSecretRef = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "hmac"},
+			Key:                  "key",
+		}
+		Expect(k8sClient.Update(ctx, target)).To(Succeed())
+
+		notifier.Deliver(ctx, key, []Notification{{Key: "PASSWORD"}})
+
+		Expect(stub.signatures).To(HaveLen(1))
+		body, err := json.Marshal(stub.payloads[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stub.signatures[0]).To(Equal("sha256=" + Sign([]byte("secret"), body)))
+	})
+
+	It("Should retry failed deliveries and drop them once all attempts failed", func() {
+		stub.failures = 2
+		notifier.Deliver(ctx, key, []Notification{{Key: "PASSWORD"}})
+		Expect(stub.requests).To(Equal(3))
+		Expect(stub.payloads).To(HaveLen(1))
+
+		stub.failures = 3
+		notifier.Deliver(ctx, key, []Notification{{Key: "TOKEN"}})
+		Expect(stub.requests).To(Equal(6))
+
+		var status configv2.NotificationTarget
+		Expect(k8sClient.Get(ctx, key, &status)).To(Succeed())
+		Expect(status.Status.Delivered).To(Equal(int64(1)))
+		Expect(status.Status.Dropped).To(Equal(int64(1)))
+		Expect(status.Status.Conditions).To(HaveLen(1))
+		Expect(status.Status.Conditions[0].Reason).To(Equal("DeliveryFailed"))
+	})
+
+	It("Should wait for a running delivery once maxDeliveries are running", func() {
+		for range maxDeliveries {
+			notifier.deliveries <- struct{}{}
+		}
+		go notifier.startDelivery(ctx, key, []Notification{{Key: "PASSWORD"}})
+		Consistently(func() int {
+			stub.Lock()
+			defer stub.Unlock()
+			return stub.requests
+		}, 100*time.Millisecond).Should(BeZero())
+
+		By("finishing a running delivery")
+		<-notifier.deliveries
+		Eventually(func() int {
+			stub.Lock()
+			defer stub.Unlock()
+			return len(stub.payloads)
+		}, 5*time.Second).Should(Equal(1))
+	})
+
+	It("Should not send notifications to internal addresses unless they are allowed", func() {
+		notifier = NewNotifier(k8sClient, k8sClient, nil)
+		notifier.Deliver(ctx, key, []Notification{{Key: "PASSWORD"}})
+		Expect(stub.requests).To(BeZero())
+
+		var status configv2.NotificationTarget
+		Expect(k8sClient.Get(ctx, key, &status)).To(Succeed())
+		Expect(status.Status.Conditions[0].Reason).To(Equal("DeliveryFailed"))
+		Expect(status.Status.Conditions[0].Message).To(ContainSubstring("127.0.0.1 is not allowed"))
+
+		By("checking addresses of other internal networks")
+		for _, addr := range []string{"10.0.0.1", "169.254.169.254", "100.100.100.200", "::1", "fd00:ec2::254", "::ffff:127.0.0.1"} {
+			Expect(isAllowed(netip.MustParseAddr(addr), nil)).To(BeFalse(), addr)
+		}
+		Expect(isAllowed(netip.MustParseAddr("93.184.216.34"), nil)).To(BeTrue())
+		allowedNetworks, err := ParseAllowedNetworks("10.96.0.0/12, 192.168.1.1/24")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(isAllowed(netip.MustParseAddr("10.96.0.10"), allowedNetworks)).To(BeTrue())
+		Expect(isAllowed(netip.MustParseAddr("192.168.1.20"), allowedNetworks)).To(BeTrue())
+		Expect(isAllowed(netip.MustParseAddr("10.0.0.1"), allowedNetworks)).To(BeFalse())
+		_, err = ParseAllowedNetworks("10.0.0.0")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should ignore violations on a nil notifier", func() {
+		var nilNotifier *Notifier
+		Expect(func() { nilNotifier.Notify(violation("PASSWORD"), "denied") }).NotTo(Panic())
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package presets holds the sets of sensitive keys shipped with the operator. Every preset has a
+// version, which changes whenever its rules change.
+package presets
+
+import (
+	"slices"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// Set is a version of a preset
+type Set struct {
+	Version string
+	Rules   []configv2.KeyRule
+}
+
+var sets = map[configv2.Preset]Set{
+	configv2.PresetCloudCredentials: {
+		Version: "v1",
+		Rules: []configv2.KeyRule{
+			{Name: "AWS_ACCESS_KEY_ID", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "AWS_SECRET_ACCESS_KEY", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "AWS_SESSION_TOKEN", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "AZURE_CLIENT_SECRET", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "AZURE_STORAGE_KEY", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "AZURE_STORAGE_CONNECTION_STRING", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "GOOGLE_APPLICATION_CREDENTIALS_JSON", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "GOOGLE_API_KEY", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "DIGITALOCEAN_ACCESS_TOKEN", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "ALIBABA_CLOUD_ACCESS_KEY_SECRET", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+		},
+	},
+	configv2.PresetDatabase: {
+		Version: "v1",
+		Rules: []configv2.KeyRule{
+			{Name: "DATABASE_URL", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "_DATABASE_URL", Match: configv2.MatchSuffix, Severity: configv2.SeverityHigh},
+			{Name: "DB_PASSWORD", Match: configv2.MatchSuffix, Severity: configv2.SeverityCritical},
+			{Name: "POSTGRES_PASSWORD", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "PGPASSWORD", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "MYSQL_PASSWORD", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "MYSQL_ROOT_PASSWORD", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "MONGODB_URI", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "MONGO_INITDB_ROOT_PASSWORD", Match: configv2.MatchExact, Severity: configv2.SeverityCritical},
+			{Name: "REDIS_PASSWORD", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+		},
+	},
+	configv2.PresetGenericSecrets: {
+		Version: "v1",
+		Rules: []configv2.KeyRule{
+			{Name: "PASSWORD", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "_PASSWORD", Match: configv2.MatchSuffix, Severity: configv2.SeverityHigh},
+			{Name: "SECRET", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "_SECRET", Match: configv2.MatchSuffix, Severity: configv2.SeverityHigh},
+			{Name: "_SECRET_KEY", Match: configv2.MatchSuffix, Severity: configv2.SeverityHigh},
+			{Name: "TOKEN", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "_TOKEN", Match: configv2.MatchSuffix, Severity: configv2.SeverityHigh},
+			{Name: "API_KEY", Match: configv2.MatchExact, Severity: configv2.SeverityHigh},
+			{Name: "_API_KEY", Match: configv2.MatchSuffix, Severity: configv2.SeverityHigh},
+			{Name: "PRIVATE_KEY", Match: configv2.MatchContains, Severity: configv2.SeverityCritical},
+		},
+	},
+}
+
+// Get returns the current version of a preset, the rules may be modified by the caller
+func Get(name configv2.Preset) (Set, bool) {
+
+	set, ok := sets[name]
+	if !ok {
+		return Set{}, false
+	}
+	set.Rules = slices.Clone(set.Rules)
+	return set, true
+}
+
+// Status returns the presets of an EnvKeyMonitor with the version evaluated, unknown presets
+// are left out
+func Status(envKeyMonitor *configv2.EnvKeyMonitor) []configv2.PresetStatus {
+
+	var status []configv2.PresetStatus
+	for _, name := range envKeyMonitor.Spec.Presets {
+		set, ok := sets[name]
+		if !ok {
+			continue
+		}
+		status = append(status, configv2.PresetStatus{
+			Name:     name,
+			Version:  set.Version,
+			KeyCount: int32(len(set.Rules)),
+		})
+	}
+	return status
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package presets
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+var _ = Describe("Presets", func() {
+	It("Should ship a version and distinct rules for every preset", func() {
+		for _, name := range []configv2.Preset{
+			configv2.PresetCloudCredentials,
+			configv2.PresetDatabase,
+			configv2.PresetGenericSecrets,
+		} {
+			set, ok := Get(name)
+			Expect(ok).To(BeTrue())
+			Expect(set.Version).NotTo(BeEmpty())
+			Expect(set.Rules).NotTo(BeEmpty())
+
+			seen := map[configv2.KeyRule]bool{}
+			for _, rule := range set.Rules {
+				id := configv2.KeyRule{Name: rule.Name, Match: rule.Match}
+				Expect(seen).NotTo(HaveKey(id))
+				Expect(rule.Severity).NotTo(BeEmpty())
+				Expect(rules.SyntaxErrors(rule)).To(BeEmpty())
+				seen[id] = true
+			}
+		}
+	})
+
+	It("Should not share rules with callers", func() {
+		set, _ := Get(configv2.PresetDatabase)
+		set.Rules[0].Name = "CHANGED"
+		set, _ = Get(configv2.PresetDatabase)
+		Expect(set.Rules[0].Name).To(Equal("DATABASE_URL"))
+	})
+
+	It("Should list the version of every known preset", func() {
+		envKeyMonitor := &configv2.EnvKeyMonitor{Spec: configv2.EnvKeyMonitorSpec{
+			Presets: []configv2.Preset{configv2.PresetDatabase, "unknown"},
+		}}
+		Expect(Status(envKeyMonitor)).To(Equal([]configv2.PresetStatus{
+			{Name: configv2.PresetDatabase, Version: "v1", KeyCount: 10},
+		}))
+	})
+})
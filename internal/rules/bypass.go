@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"slices"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// Bypasses returns true if the requester is listed in .spec.bypass of an EnvKeyMonitor, by
+// username, by one of their groups or as a ServiceAccount
+func Bypasses(envKeyMonitor *configv2.EnvKeyMonitor, requester *configv2.Requester) bool {
+
+	bypass := envKeyMonitor.Spec.Bypass
+	if bypass == nil || requester == nil {
+		return false
+	}
+	if slices.Contains(bypass.Users, requester.Username) {
+		return true
+	}
+	for _, group := range requester.Groups {
+		if slices.Contains(bypass.Groups, group) {
+			return true
+		}
+	}
+	for _, serviceAccount := range bypass.ServiceAccounts {
+		namespace := serviceAccount.Namespace
+		if namespace == "" {
+			namespace = envKeyMonitor.GetNamespace()
+		}
+		if requester.Username == fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount.Name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("Bypass", func() {
+	envKeyMonitor := &configv2.EnvKeyMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+		Spec: configv2.EnvKeyMonitorSpec{
+			Bypass: &configv2.Bypass{
+				Users:  []string{"jane"},
+				Groups: []string{"migration"},
+				ServiceAccounts: []configv2.BypassServiceAccount{
+					{Name: "ci"},
+					{Name: "deployer", Namespace: "tools"},
+				},
+			},
+		},
+	}
+
+	DescribeTable("Should match the requester against the bypass subjects",
+		func(requester *configv2.Requester, expected bool) {
+			Expect(Bypasses(envKeyMonitor, requester)).To(Equal(expected))
+		},
+		Entry("listed user", &configv2.Requester{Username: "jane"}, true),
+		Entry("other user", &configv2.Requester{Username: "john", Groups: []string{"developers"}}, false),
+		Entry("listed group", &configv2.Requester{Username: "john", Groups: []string{"developers", "migration"}}, true),
+		Entry("service account in the namespace", &configv2.Requester{Username: "system:serviceaccount:default:ci"}, true),
+		Entry("service account in another namespace", &configv2.Requester{Username: "system:serviceaccount:other:ci"}, false),
+		Entry("service account with namespace", &configv2.Requester{Username: "system:serviceaccount:tools:deployer"}, true),
+		Entry("unknown requester", nil, false),
+	)
+})
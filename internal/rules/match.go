@@ -0,0 +1,425 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// ConfigMap fields holding keys
+const (
+	FieldData       = "data"
+	FieldBinaryData = "binaryData"
+)
+
+// Matches checks if a key is matched by a rule
+func Matches(rule configv2.KeyRule, key string) bool {
+
+	switch rule.Match {
+	case configv2.MatchPrefix:
+		return strings.HasPrefix(key, rule.Name)
+	case configv2.MatchSuffix:
+		return strings.HasSuffix(key, rule.Name)
+	case configv2.MatchContains:
+		return strings.Contains(key, rule.Name)
+	case configv2.MatchRegex:
+		// Patterns are validated when the EnvKeyMonitor is admitted
+		pattern, err := regexp.Compile(rule.Name)
+		return err == nil && pattern.MatchString(key)
+	default:
+		return key == rule.Name
+	}
+}
+
+// Find returns all keys of a ConfigMap matched by the rules of the EnvKeyMonitor objects, keys
+// matched by a rule with a value regular expression only if their value matches as well.
+// Allowlist objects instead report every key not matched by any of their rules as unapproved,
+// objects with a key name policy report every key not following it, and objects with required
+// keys report every required key missing from a ConfigMap they select.
+// The rules of all objects are evaluated as a union: a key matched by the same rule of several
+// objects is reported once, preferring enforcing objects over Shadow and Audit ones, and a
+// STRICT object over a PERMISSIVE one.
+// Rules are evaluated by a compiled matcher, so the cost of a key does not grow with the number
+// of rules. A key matched by several rules of the same object is reported once, by the rule with
+// the strictest action and the highest severity.
+// Violations are reported ordered by object, rule, field and key
+func Find(envKeyMonitorList *configv2.EnvKeyMonitorList, configmap *corev1.ConfigMap) []Violation {
+
+	fieldNames := []string{FieldData, FieldBinaryData}
+	fields := [][]string{
+		slices.Sorted(maps.Keys(configmap.Data)),
+		slices.Sorted(maps.Keys(configmap.BinaryData)),
+	}
+
+	// Collect the position of every match
+	type match struct {
+		entry
+		field int
+		key   int
+	}
+	envKeyMonitors := sortedByName(envKeyMonitorList)
+	compiled := matcherFor(envKeyMonitors)
+	var matches []match
+	for field, keys := range fields {
+		for key, name := range keys {
+			compiled.match(name, func(e entry) {
+				matches = append(matches, match{entry: e, field: field, key: key})
+			})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(a.monitor, b.monitor),
+			cmp.Compare(a.rule, b.rule),
+			cmp.Compare(a.field, b.field),
+			cmp.Compare(a.key, b.key),
+		)
+	})
+	matches = slices.Compact(matches)
+
+	var violations []Violation
+	seen := map[string]int{}
+	// Keys approved by Allowlist objects, by object, field and key
+	type position struct{ monitor, field, key int }
+	approved := map[position]bool{}
+	for _, m := range matches {
+		envKeyMonitor := envKeyMonitors[m.monitor]
+		rule := envKeyMonitor.Spec.Rules[m.rule]
+		fieldName := fieldNames[m.field]
+		key := fields[m.field][m.key]
+
+		// Rules with a value regular expression only match keys holding a matching value
+		if !compiled.matchesValue(m.entry, func() []byte { return value(configmap, fieldName, key) }) {
+			continue
+		}
+
+		// Keys matched by a rule of an Allowlist object are approved by it
+		if ListTypeOf(envKeyMonitor) == configv2.ListTypeAllowlist {
+			approved[position{m.monitor, m.field, m.key}] = true
+			continue
+		}
+
+		violation := Violation{
+			Monitor:   envKeyMonitor,
+			Rule:      rule,
+			Field:     fieldName,
+			Key:       key,
+			ConfigMap: configmap.GetName(),
+			Namespace: configmap.GetNamespace(),
+		}
+
+		// Same rule held by another object
+		id := strings.Join([]string{rule.Name, string(rule.Match), rule.ValueRegex, fieldName, key}, "/")
+		if i, ok := seen[id]; ok {
+			if rank(envKeyMonitor) > rank(violations[i].Monitor) {
+				violations[i] = violation
+			}
+			continue
+		}
+		seen[id] = len(violations)
+		violations = append(violations, violation)
+	}
+	violations = strictestRules(violations)
+
+	// Required keys missing from the ConfigMap, keys not approved by an Allowlist object and keys
+	// not following the key name policy of an object, reported once per kind if several objects
+	// report them
+	reported := map[string]int{}
+	report := func(violation Violation) {
+		id := strings.Join([]string{string(violation.Kind), violation.Field, violation.Key}, "/")
+		if i, ok := reported[id]; ok {
+			if rank(violation.Monitor) > rank(violations[i].Monitor) {
+				violations[i] = violation
+			}
+			return
+		}
+		reported[id] = len(violations)
+		violations = append(violations, violation)
+	}
+	for i, envKeyMonitor := range envKeyMonitors {
+		for _, key := range MissingKeys(envKeyMonitor, configmap) {
+			report(Violation{
+				Monitor:   envKeyMonitor,
+				Kind:      configv2.ViolationKindMissingKey,
+				Field:     FieldData,
+				Key:       key,
+				ConfigMap: configmap.GetName(),
+				Namespace: configmap.GetNamespace(),
+			})
+		}
+		allowlist := ListTypeOf(envKeyMonitor) == configv2.ListTypeAllowlist
+		keyNamePolicy := envKeyMonitor.Spec.KeyNamePolicy
+		if !allowlist && keyNamePolicy == nil {
+			continue
+		}
+		for field, keys := range fields {
+			for key, name := range keys {
+				violation := Violation{
+					Monitor:   envKeyMonitor,
+					Field:     fieldNames[field],
+					Key:       name,
+					ConfigMap: configmap.GetName(),
+					Namespace: configmap.GetNamespace(),
+				}
+				if allowlist && !approved[position{i, field, key}] {
+					violation.Kind = configv2.ViolationKindUnapprovedKey
+					report(violation)
+				}
+				if detail := checkKeyName(keyNamePolicy, name); detail != "" {
+					violation.Kind = configv2.ViolationKindKeyName
+					violation.Detail = detail
+					report(violation)
+				}
+			}
+		}
+	}
+	slices.SortStableFunc(violations, func(a, b Violation) int {
+		return strings.Compare(a.Monitor.GetName(), b.Monitor.GetName())
+	})
+	return violations
+}
+
+// Number of compiled key name policy patterns kept for reuse across admission requests
+const maxCachedKeyNamePatterns = 64
+
+// Compiled patterns of key name policies
+var keyNamePatterns = struct {
+	sync.Mutex
+	cache map[string]*regexp.Regexp
+}{cache: map[string]*regexp.Regexp{}}
+
+// Get the compiled pattern of a key name policy, compiling it if it is not cached. The cache is
+// cleared once it holds maxCachedKeyNamePatterns patterns
+func keyNamePatternFor(pattern string) *regexp.Regexp {
+
+	keyNamePatterns.Lock()
+	compiled, ok := keyNamePatterns.cache[pattern]
+	keyNamePatterns.Unlock()
+	if ok {
+		return compiled
+	}
+
+	// Patterns are validated when the EnvKeyMonitor is admitted, invalid patterns never report a key
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		compiled = nil
+	}
+	keyNamePatterns.Lock()
+	defer keyNamePatterns.Unlock()
+	if len(keyNamePatterns.cache) >= maxCachedKeyNamePatterns {
+		clear(keyNamePatterns.cache)
+	}
+	keyNamePatterns.cache[pattern] = compiled
+	return compiled
+}
+
+// Check if a key follows a key name policy. Returns why it does not, or an empty string
+func checkKeyName(keyNamePolicy *configv2.KeyNamePolicy, key string) string {
+
+	if keyNamePolicy == nil {
+		return ""
+	}
+	var details []string
+	if keyNamePolicy.MaxLength != nil && len(key) > int(*keyNamePolicy.MaxLength) {
+		details = append(details, fmt.Sprintf("it is longer than %d characters", *keyNamePolicy.MaxLength))
+	}
+	if keyNamePolicy.Pattern != "" {
+		if compiled := keyNamePatternFor(keyNamePolicy.Pattern); compiled != nil && !compiled.MatchString(key) {
+			details = append(details, fmt.Sprintf("it does not match '%s'", keyNamePolicy.Pattern))
+		}
+	}
+	return strings.Join(details, " and ")
+}
+
+// MissingKeys returns the required keys of an EnvKeyMonitor missing from a ConfigMap selected by
+// them, sorted and without duplicates
+func MissingKeys(envKeyMonitor *configv2.EnvKeyMonitor, configmap *corev1.ConfigMap) []string {
+
+	var missing []string
+	for _, requiredKeys := range envKeyMonitor.Spec.RequiredKeys {
+		if !Selects(requiredKeys, configmap) {
+			continue
+		}
+		for _, key := range requiredKeys.Keys {
+			_, inData := configmap.Data[key]
+			_, inBinaryData := configmap.BinaryData[key]
+			if !inData && !inBinaryData {
+				missing = append(missing, key)
+			}
+		}
+	}
+	slices.Sort(missing)
+	return slices.Compact(missing)
+}
+
+// Selects checks if the ConfigMap selector of required keys selects a ConfigMap
+func Selects(requiredKeys configv2.RequiredKeys, configmap *corev1.ConfigMap) bool {
+
+	// Selectors are validated when the EnvKeyMonitor is admitted, invalid selectors select nothing
+	selector, err := metav1.LabelSelectorAsSelector(&requiredKeys.ConfigMapSelector)
+	return err == nil && selector.Matches(labels.Set(configmap.GetLabels()))
+}
+
+// Get the value of a key of a ConfigMap
+func value(configmap *corev1.ConfigMap, fieldName, key string) []byte {
+
+	if fieldName == FieldBinaryData {
+		return configmap.BinaryData[key]
+	}
+	return []byte(configmap.Data[key])
+}
+
+// ModeOf returns the mode of an EnvKeyMonitor, objects without a mode are enforced
+func ModeOf(envKeyMonitor *configv2.EnvKeyMonitor) configv2.Mode {
+
+	if envKeyMonitor.Spec.Mode == "" {
+		return configv2.ModeEnforce
+	}
+	return envKeyMonitor.Spec.Mode
+}
+
+// ListTypeOf returns the list type of an EnvKeyMonitor, rules of objects without a list type
+// list forbidden keys
+func ListTypeOf(envKeyMonitor *configv2.EnvKeyMonitor) configv2.ListType {
+
+	if envKeyMonitor.Spec.ListType == "" {
+		return configv2.ListTypeDenylist
+	}
+	return envKeyMonitor.Spec.ListType
+}
+
+// Get how strongly the violations of an EnvKeyMonitor are acted upon
+func rank(envKeyMonitor *configv2.EnvKeyMonitor) int {
+
+	rank := 0
+	switch ModeOf(envKeyMonitor) {
+	case configv2.ModeEnforce:
+		rank = 4
+	case configv2.ModeShadow:
+		rank = 2
+	}
+	if envKeyMonitor.Spec.Policy == configv2.PolicyStrict {
+		rank++
+	}
+	return rank
+}
+
+// Keep a single violation per object, field and key, reported by the rule with the strictest
+// action and the highest severity, or by the first of them
+func strictestRules(violations []Violation) []Violation {
+
+	var strictest []Violation
+	seen := map[string]int{}
+	for _, violation := range violations {
+		id := strings.Join([]string{violation.Monitor.GetName(), violation.Field, violation.Key}, "/")
+		if i, ok := seen[id]; ok {
+			if ruleRank(violation) > ruleRank(strictest[i]) {
+				strictest[i] = violation
+			}
+			continue
+		}
+		seen[id] = len(strictest)
+		strictest = append(strictest, violation)
+	}
+	return strictest
+}
+
+// Get the rank of the rule of a violation within its object, higher ranks take a stricter action
+// or are more severe
+func ruleRank(violation Violation) int {
+
+	rank := 0
+	switch violation.Action() {
+	case configv2.SeverityActionDeny:
+		rank = 10
+	case configv2.SeverityActionWarn:
+		rank = 5
+	}
+	switch violation.Rule.Severity {
+	case configv2.SeverityCritical:
+		rank += 4
+	case configv2.SeverityHigh:
+		rank += 3
+	case configv2.SeverityMedium:
+		rank += 2
+	case configv2.SeverityLow:
+		rank++
+	}
+	return rank
+}
+
+// Get the EnvKeyMonitor objects sorted by name, so results do not depend on the order of the list
+func sortedByName(envKeyMonitorList *configv2.EnvKeyMonitorList) []*configv2.EnvKeyMonitor {
+
+	envKeyMonitors := make([]*configv2.EnvKeyMonitor, 0, len(envKeyMonitorList.Items))
+	for i := range envKeyMonitorList.Items {
+		envKeyMonitors = append(envKeyMonitors, &envKeyMonitorList.Items[i])
+	}
+	slices.SortFunc(envKeyMonitors, func(a, b *configv2.EnvKeyMonitor) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return envKeyMonitors
+}
+
+// KeyChanged checks if the key of a violation was added or modified by an update of the ConfigMap,
+// or for a missing key removed by it
+func KeyChanged(oldConfigmap, configmap *corev1.ConfigMap, violation Violation) bool {
+
+	// A missing key is changed by an update removing it, or selecting a ConfigMap lacking it
+	if violation.Kind == configv2.ViolationKindMissingKey {
+		_, inData := oldConfigmap.Data[violation.Key]
+		_, inBinaryData := oldConfigmap.BinaryData[violation.Key]
+		return inData || inBinaryData || !slices.Contains(MissingKeys(violation.Monitor, oldConfigmap), violation.Key)
+	}
+	switch violation.Field {
+	case FieldBinaryData:
+		oldValue, ok := oldConfigmap.BinaryData[violation.Key]
+		return !ok || !bytes.Equal(oldValue, configmap.BinaryData[violation.Key])
+	default:
+		oldValue, ok := oldConfigmap.Data[violation.Key]
+		return !ok || oldValue != configmap.Data[violation.Key]
+	}
+}
+
+// Reduces checks if the violations of an updated ConfigMap are a strict subset of the
+// violations before the update
+func Reduces(oldViolations, violations []Violation) bool {
+
+	old := make(map[string]bool, len(oldViolations))
+	for _, violation := range oldViolations {
+		old[violation.id()] = true
+	}
+	for _, violation := range violations {
+		if !old[violation.id()] {
+			return false
+		}
+	}
+	return len(violations) < len(old)
+}
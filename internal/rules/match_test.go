@@ -0,0 +1,273 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("Match", func() {
+	var (
+		envKeyMonitorList *configv2.EnvKeyMonitorList
+		configmap         *corev1.ConfigMap
+	)
+
+	BeforeEach(func() {
+		envKeyMonitorList = &configv2.EnvKeyMonitorList{
+			Items: []configv2.EnvKeyMonitor{{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+				Spec: configv2.EnvKeyMonitorSpec{
+					Rules: []configv2.KeyRule{
+						{Name: "API_KEY", Match: configv2.MatchExact},
+						{Name: "_TOKEN", Match: configv2.MatchSuffix},
+					},
+				},
+			}},
+		}
+		configmap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "configmap", Namespace: "default"},
+			Data:       map[string]string{"API_KEY": "value", "LOG_LEVEL": "debug"},
+			BinaryData: map[string][]byte{"GITHUB_TOKEN": []byte("value")},
+		}
+	})
+
+	It("Should match keys by each match type", func() {
+		Expect(Matches(configv2.KeyRule{Name: "API_KEY"}, "API_KEY")).To(BeTrue())
+		Expect(Matches(configv2.KeyRule{Name: "API_KEY"}, "MY_API_KEY")).To(BeFalse())
+		Expect(Matches(configv2.KeyRule{Name: "AWS_", Match: configv2.MatchPrefix}, "AWS_REGION")).To(BeTrue())
+		Expect(Matches(configv2.KeyRule{Name: "_TOKEN", Match: configv2.MatchSuffix}, "GH_TOKEN")).To(BeTrue())
+		Expect(Matches(configv2.KeyRule{Name: "SECRET", Match: configv2.MatchContains}, "MY_SECRET_X")).To(BeTrue())
+		Expect(Matches(configv2.KeyRule{Name: "^DB_.*$", Match: configv2.MatchRegex}, "DB_URL")).To(BeTrue())
+		Expect(Matches(configv2.KeyRule{Name: "^DB_.*$", Match: configv2.MatchRegex}, "MY_DB_URL")).To(BeFalse())
+	})
+
+	It("Should find violations in data and binaryData", func() {
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Path().String()).To(Equal("data[API_KEY]"))
+		Expect(violations[1].Path().String()).To(Equal("binaryData[GITHUB_TOKEN]"))
+	})
+
+	It("Should only report keys holding a matching value for rules with a value regex", func() {
+		envKeyMonitorList.Items[0].Spec.Rules = []configv2.KeyRule{
+			{Name: ".*", Match: configv2.MatchRegex, ValueRegex: "^ghp_"},
+			{Name: "API_KEY", ValueRegex: "("},
+		}
+		configmap.Data["TOKEN"] = "ghp_0123456789"
+		configmap.BinaryData["BINARY_TOKEN"] = []byte("ghp_0123456789")
+
+		var paths []string
+		for _, violation := range Find(envKeyMonitorList, configmap) {
+			paths = append(paths, violation.Path().String())
+		}
+		Expect(paths).To(Equal([]string{"data[TOKEN]", "binaryData[BINARY_TOKEN]"}))
+	})
+
+	It("Should report a key matched by the same rule of several objects once", func() {
+		shared := envKeyMonitorList.Items[0].DeepCopy()
+		shared.Name = "another-monitor"
+		shared.Spec.Policy = configv2.PolicyStrict
+		envKeyMonitorList.Items = append(envKeyMonitorList.Items, *shared)
+
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Monitor.GetName()).To(Equal("another-monitor"))
+		Expect(violations[1].Monitor.GetName()).To(Equal("another-monitor"))
+
+		By("preferring enforcing objects over Shadow objects")
+		shared.Spec.Mode = configv2.ModeShadow
+		envKeyMonitorList.Items[1] = *shared
+		violations = Find(envKeyMonitorList, configmap)
+		Expect(violations[0].Monitor.GetName()).To(Equal("monitor"))
+
+		By("reporting keys matched by different rules separately")
+		shared.Spec.Rules = []configv2.KeyRule{{Name: "API_", Match: configv2.MatchPrefix}}
+		envKeyMonitorList.Items[1] = *shared
+		Expect(Find(envKeyMonitorList, configmap)).To(HaveLen(3))
+	})
+
+	It("Should report every key not approved by an Allowlist object", func() {
+		envKeyMonitorList.Items = append(envKeyMonitorList.Items, configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "allowlist", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				ListType: configv2.ListTypeAllowlist,
+				Rules: []configv2.KeyRule{
+					{Name: "LOG_", Match: configv2.MatchPrefix},
+					{Name: "GITHUB_TOKEN", ValueRegex: "^ghp_"},
+				},
+			},
+		})
+
+		var paths []string
+		violations := Find(envKeyMonitorList, configmap)
+		for _, violation := range violations {
+			paths = append(paths, violation.Monitor.GetName()+"/"+violation.Path().String())
+		}
+		Expect(paths).To(Equal([]string{
+			"allowlist/data[API_KEY]",
+			"allowlist/binaryData[GITHUB_TOKEN]",
+			"monitor/data[API_KEY]",
+			"monitor/binaryData[GITHUB_TOKEN]",
+		}))
+		Expect(violations[0].Kind).To(Equal(configv2.ViolationKindUnapprovedKey))
+		Expect(violations[0].Rule).To(BeZero())
+		Expect(violations[0].Message()).To(Equal(
+			"Configmap contains key that is not approved and is therefore invalid. Unapproved key is 'API_KEY'",
+		))
+
+		By("approving keys holding a value matching the value regex")
+		configmap.BinaryData["GITHUB_TOKEN"] = []byte("ghp_0123456789")
+		Expect(Find(envKeyMonitorList, configmap)).To(HaveLen(3))
+	})
+
+	It("Should report every key not following the key name policy", func() {
+		envKeyMonitorList.Items[0].Spec.Rules = nil
+		envKeyMonitorList.Items[0].Spec.KeyNamePolicy = &configv2.KeyNamePolicy{
+			Pattern:   "^[A-Z][A-Z0-9_]*$",
+			MaxLength: ptr.To[int32](10),
+		}
+		configmap.Data["log-level"] = "debug"
+
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Kind).To(Equal(configv2.ViolationKindKeyName))
+		Expect(violations[0].Path().String()).To(Equal("data[log-level]"))
+		Expect(violations[0].Message()).To(Equal("Configmap contains key not following the naming convention and is " +
+			"therefore invalid. Key 'log-level' is invalid, it does not match '^[A-Z][A-Z0-9_]*$'"))
+		Expect(violations[1].Path().String()).To(Equal("binaryData[GITHUB_TOKEN]"))
+		Expect(violations[1].Detail).To(Equal("it is longer than 10 characters"))
+
+		By("ignoring invalid patterns")
+		envKeyMonitorList.Items[0].Spec.KeyNamePolicy = &configv2.KeyNamePolicy{Pattern: "^[A-Z"}
+		Expect(Find(envKeyMonitorList, configmap)).To(BeEmpty())
+	})
+
+	It("Should report a key matched by several rules of an object once", func() {
+		envKeyMonitorList.Items[0].Spec.Rules = []configv2.KeyRule{
+			{Name: "_PASSWORD", Match: configv2.MatchSuffix, Severity: configv2.SeverityMedium},
+			{Name: "DB_PASSWORD", Match: configv2.MatchSuffix, Severity: configv2.SeverityCritical},
+			{Name: "DB_", Match: configv2.MatchPrefix},
+		}
+		envKeyMonitorList.Items[0].Spec.SeverityPolicy = []configv2.SeverityPolicy{
+			{Severity: configv2.SeverityMedium, Action: configv2.SeverityActionWarn},
+			{Severity: configv2.SeverityCritical, Action: configv2.SeverityActionDeny},
+		}
+		configmap.Data = map[string]string{"DB_PASSWORD": "secret", "DB_HOST": "db"}
+
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Key).To(Equal("DB_PASSWORD"))
+		Expect(violations[0].Rule.Name).To(Equal("DB_PASSWORD"))
+		Expect(violations[1].Key).To(Equal("DB_HOST"))
+		Expect(violations[1].Rule.Name).To(Equal("DB_"))
+	})
+
+	It("Should keep a bounded number of key name policy patterns", func() {
+		envKeyMonitorList.Items[0].Spec.Rules = nil
+		for i := range 2 * maxCachedKeyNamePatterns {
+			envKeyMonitorList.Items[0].Spec.KeyNamePolicy = &configv2.KeyNamePolicy{Pattern: fmt.Sprintf("^KEY_%d$", i)}
+			Expect(Find(envKeyMonitorList, configmap)).NotTo(BeEmpty())
+		}
+		keyNamePatterns.Lock()
+		defer keyNamePatterns.Unlock()
+		Expect(len(keyNamePatterns.cache)).To(BeNumerically("<=", maxCachedKeyNamePatterns))
+	})
+
+	It("Should report required keys missing from selected configmaps", func() {
+		envKeyMonitorList.Items[0].Spec.Rules = nil
+		envKeyMonitorList.Items[0].Spec.RequiredKeys = []configv2.RequiredKeys{
+			{
+				ConfigMapSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				Keys:              []string{"SERVICE_NAME", "LOG_LEVEL", "GITHUB_TOKEN"},
+			},
+			{
+				ConfigMapSelector: metav1.LabelSelector{},
+				Keys:              []string{"SERVICE_NAME"},
+			},
+		}
+
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Kind).To(Equal(configv2.ViolationKindMissingKey))
+		Expect(violations[0].Path().String()).To(Equal("data[SERVICE_NAME]"))
+		Expect(violations[0].Message()).To(Equal(
+			"Configmap is missing a required key and is therefore invalid. Missing key is 'SERVICE_NAME'",
+		))
+
+		By("checking the keys of configmaps selected by label")
+		configmap.Labels = map[string]string{"app": "api"}
+		Expect(Find(envKeyMonitorList, configmap)).To(HaveLen(1))
+		delete(configmap.Data, "LOG_LEVEL")
+		Expect(MissingKeys(&envKeyMonitorList.Items[0], configmap)).To(Equal([]string{"LOG_LEVEL", "SERVICE_NAME"}))
+	})
+
+	It("Should detect removed required keys and newly selected configmaps", func() {
+		envKeyMonitorList.Items[0].Spec.RequiredKeys = []configv2.RequiredKeys{{
+			ConfigMapSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			Keys:              []string{"LOG_LEVEL", "SERVICE_NAME"},
+		}}
+		configmap.Labels = map[string]string{"app": "api"}
+		oldConfigmap := configmap.DeepCopy()
+		delete(configmap.Data, "LOG_LEVEL")
+
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(4))
+		Expect(violations[2].Key).To(Equal("LOG_LEVEL"))
+		Expect(KeyChanged(oldConfigmap, configmap, violations[2])).To(BeTrue())
+		Expect(violations[3].Key).To(Equal("SERVICE_NAME"))
+		Expect(KeyChanged(oldConfigmap, configmap, violations[3])).To(BeFalse())
+
+		oldConfigmap.Labels = nil
+		Expect(KeyChanged(oldConfigmap, configmap, violations[3])).To(BeTrue())
+	})
+
+	It("Should detect added and modified keys", func() {
+		oldConfigmap := configmap.DeepCopy()
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(KeyChanged(oldConfigmap, configmap, violations[0])).To(BeFalse())
+		Expect(KeyChanged(oldConfigmap, configmap, violations[1])).To(BeFalse())
+
+		configmap.Data["API_KEY"] = "other"
+		configmap.BinaryData["GITHUB_TOKEN"] = []byte("other")
+		Expect(KeyChanged(oldConfigmap, configmap, violations[0])).To(BeTrue())
+		Expect(KeyChanged(oldConfigmap, configmap, violations[1])).To(BeTrue())
+
+		delete(oldConfigmap.Data, "API_KEY")
+		configmap.Data["API_KEY"] = "value"
+		Expect(KeyChanged(oldConfigmap, configmap, violations[0])).To(BeTrue())
+	})
+
+	It("Should detect updates reducing the number of violations", func() {
+		oldViolations := Find(envKeyMonitorList, configmap)
+		Expect(Reduces(oldViolations, oldViolations)).To(BeFalse())
+
+		delete(configmap.Data, "API_KEY")
+		Expect(Reduces(oldViolations, Find(envKeyMonitorList, configmap))).To(BeTrue())
+
+		configmap.Data["OTHER_TOKEN"] = "value"
+		Expect(Reduces(oldViolations, Find(envKeyMonitorList, configmap))).To(BeFalse())
+	})
+})
@@ -0,0 +1,460 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"crypto/sha256"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// Number of compiled matchers kept for reuse across admission requests
+const maxCachedMatchers = 64
+
+// entry identifies a rule by the position of its EnvKeyMonitor and its position in .spec.rules
+type entry struct {
+	monitor int
+	rule    int
+}
+
+// regexEntry is a regular expression and the rules matching by it, rules sharing a pattern
+// evaluate it once
+type regexEntry struct {
+	pattern *regexp.Regexp
+	entries []entry
+}
+
+// matcher finds the rules matching a key without evaluating every rule. Exact rules are looked up
+// in a hash set, prefixes and suffixes in tries, substrings with an Aho-Corasick automaton.
+// Regular expressions requiring a literal are only evaluated if the key starts with, ends with or
+// contains the literal, the remaining ones are prefiltered by a single combined program if they
+// can be compiled into one
+type matcher struct {
+	exact    map[string][]entry
+	prefixes *trie
+	suffixes *trie
+	contains *automaton
+	// folded holds the case-folded literals of case-insensitive regular expressions
+	folded *automaton
+	// regexps are the distinct regular expressions of all rules, the automatons report them by index
+	regexps []regexEntry
+	// unprefixed are the indexes of regexps without a literal, combined is nil if there are none or
+	// they cannot be compiled into a single program
+	unprefixed []int
+	combined   *regexp.Regexp
+	// values are the value regular expressions of rules with .valueRegex, nil if it is invalid
+	values map[entry]*regexp.Regexp
+}
+
+// Compile a matcher for the rules of the EnvKeyMonitor objects. Rules matching by an invalid
+// regular expression never match
+func compile(envKeyMonitors []*configv2.EnvKeyMonitor) *matcher {
+
+	m := &matcher{
+		exact:    map[string][]entry{},
+		prefixes: newTrie(),
+		suffixes: newTrie(),
+		contains: newAutomaton(),
+		folded:   newAutomaton(),
+		values:   map[entry]*regexp.Regexp{},
+	}
+	var patterns []string
+	regexps := map[string]int{}
+	for i, envKeyMonitor := range envKeyMonitors {
+		for j, rule := range envKeyMonitor.Spec.Rules {
+			e := entry{monitor: i, rule: j}
+			if rule.ValueRegex != "" {
+				m.values[e], _ = regexp.Compile(rule.ValueRegex)
+			}
+			switch rule.Match {
+			case configv2.MatchPrefix:
+				m.prefixes.insert(rule.Name, e, -1)
+			case configv2.MatchSuffix:
+				m.suffixes.insert(reverse(rule.Name), e, -1)
+			case configv2.MatchContains:
+				m.contains.insert(rule.Name, e, -1)
+			case configv2.MatchRegex:
+				if index, ok := regexps[rule.Name]; ok {
+					m.regexps[index].entries = append(m.regexps[index].entries, e)
+					continue
+				}
+				pattern, err := regexp.Compile(rule.Name)
+				if err != nil {
+					continue
+				}
+				index := len(m.regexps)
+				regexps[rule.Name] = index
+				m.regexps = append(m.regexps, regexEntry{pattern: pattern, entries: []entry{e}})
+				prefix, suffix, literal, foldCase := requiredLiterals(rule.Name)
+				switch {
+				case prefix != "" && len(prefix) >= len(suffix):
+					m.prefixes.insert(prefix, entry{}, index)
+				case suffix != "":
+					m.suffixes.insert(reverse(suffix), entry{}, index)
+				case literal == "":
+					m.unprefixed = append(m.unprefixed, index)
+					patterns = append(patterns, "(?:"+rule.Name+")")
+				case foldCase:
+					m.folded.insert(fold(literal), entry{}, index)
+				default:
+					m.contains.insert(literal, entry{}, index)
+				}
+			default:
+				m.exact[rule.Name] = append(m.exact[rule.Name], e)
+			}
+		}
+	}
+	m.contains.build()
+	m.folded.build()
+	if len(patterns) > 0 {
+		// Patterns valid on their own can fail to compile combined, e.g. a trailing \Q quoting the
+		// closing parenthesis or a program too large. Each pattern is then evaluated on its own
+		m.combined, _ = regexp.Compile(strings.Join(patterns, "|"))
+	}
+	return m
+}
+
+// Check if a value satisfies the value regular expression of a rule, rules without one accept
+// every value
+func (m *matcher) matchesValue(e entry, value func() []byte) bool {
+
+	pattern, ok := m.values[e]
+	if !ok {
+		return true
+	}
+	return pattern != nil && pattern.Match(value())
+}
+
+// Call fn for every rule matching a key. A rule matching by substring or regular expression may be
+// reported more than once
+func (m *matcher) match(key string, fn func(entry)) {
+
+	for _, e := range m.exact[key] {
+		fn(e)
+	}
+	candidate := func(e entry, regex int) {
+		if regex < 0 {
+			fn(e)
+		} else if m.regexps[regex].pattern.MatchString(key) {
+			for _, e := range m.regexps[regex].entries {
+				fn(e)
+			}
+		}
+	}
+	m.prefixes.walk(key, false, candidate)
+	m.suffixes.walk(key, true, candidate)
+	m.contains.search(key, candidate)
+	if len(m.folded.states) > 1 {
+		m.folded.search(fold(key), candidate)
+	}
+	if len(m.unprefixed) > 0 && (m.combined == nil || m.combined.MatchString(key)) {
+		for _, i := range m.unprefixed {
+			if m.regexps[i].pattern.MatchString(key) {
+				for _, e := range m.regexps[i].entries {
+					fn(e)
+				}
+			}
+		}
+	}
+}
+
+// trie stores entries under byte strings, walking a key reports the entries of all its prefixes
+type trie struct {
+	children map[byte]*trie
+	entries  []candidate
+}
+
+func newTrie() *trie {
+	return &trie{children: map[byte]*trie{}}
+}
+
+func (t *trie) insert(name string, e entry, regex int) {
+
+	node := t
+	for i := 0; i < len(name); i++ {
+		child, ok := node.children[name[i]]
+		if !ok {
+			child = newTrie()
+			node.children[name[i]] = child
+		}
+		node = child
+	}
+	node.entries = append(node.entries, candidate{entry: e, regex: regex})
+}
+
+// Report the entries stored under every prefix of key, or of the reversed key
+func (t *trie) walk(key string, reversed bool, fn func(entry, int)) {
+
+	node := t
+	for i := 0; ; i++ {
+		for _, e := range node.entries {
+			fn(e.entry, e.regex)
+		}
+		if i == len(key) {
+			return
+		}
+		c := key[i]
+		if reversed {
+			c = key[len(key)-1-i]
+		}
+		child, ok := node.children[c]
+		if !ok {
+			return
+		}
+		node = child
+	}
+}
+
+// automaton is an Aho-Corasick automaton reporting all inserted strings contained in a key
+type automaton struct {
+	states []state
+}
+
+type state struct {
+	next map[byte]int
+	// fail is the state of the longest proper suffix that is also a prefix of an inserted string
+	fail int
+	// output is the nearest state on the fail chain with outputs, -1 if there is none
+	output  int
+	entries []candidate
+}
+
+// candidate is a rule stored in a trie or automaton, or a regular expression to evaluate
+type candidate struct {
+	entry
+	// regex is the index of the regular expression the string is a literal of, -1 for rules
+	// matching by prefix, suffix or substring. The entry is only set for those rules
+	regex int
+}
+
+func newAutomaton() *automaton {
+	return &automaton{states: []state{{next: map[byte]int{}, output: -1}}}
+}
+
+func (a *automaton) insert(name string, e entry, regex int) {
+
+	current := 0
+	for i := 0; i < len(name); i++ {
+		next, ok := a.states[current].next[name[i]]
+		if !ok {
+			a.states = append(a.states, state{next: map[byte]int{}, output: -1})
+			next = len(a.states) - 1
+			a.states[current].next[name[i]] = next
+		}
+		current = next
+	}
+	a.states[current].entries = append(a.states[current].entries, candidate{entry: e, regex: regex})
+}
+
+// Compute the fail and output links breadth first, once all strings are inserted
+func (a *automaton) build() {
+
+	queue := []int{}
+	for _, child := range a.states[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for c, child := range a.states[current].next {
+			fail := a.states[current].fail
+			for {
+				if next, ok := a.states[fail].next[c]; ok && next != child {
+					a.states[child].fail = next
+					break
+				}
+				if fail == 0 {
+					a.states[child].fail = 0
+					break
+				}
+				fail = a.states[fail].fail
+			}
+			failState := a.states[child].fail
+			if len(a.states[failState].entries) > 0 {
+				a.states[child].output = failState
+			} else {
+				a.states[child].output = a.states[failState].output
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Report the entries of all inserted strings contained in key, once per occurrence
+func (a *automaton) search(key string, fn func(entry, int)) {
+
+	current := 0
+	for i := 0; i < len(key); i++ {
+		for {
+			if next, ok := a.states[current].next[key[i]]; ok {
+				current = next
+				break
+			}
+			if current == 0 {
+				break
+			}
+			current = a.states[current].fail
+		}
+		for s := current; s > 0; s = a.states[s].output {
+			for _, e := range a.states[s].entries {
+				fn(e.entry, e.regex)
+			}
+		}
+	}
+}
+
+// Get the literals every match of a regular expression starts with, ends with and contains.
+// The contained literal is the longest one and may be matched case-insensitively, prefix and
+// suffix are only set if they are matched case-sensitively
+func requiredLiterals(pattern string) (prefix, suffix, literal string, foldCase bool) {
+
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", "", "", false
+	}
+	re = re.Simplify()
+	literal, foldCase = longestLiteral(re)
+
+	if re.Op != syntax.OpConcat || len(re.Sub) < 2 {
+		return "", "", literal, foldCase
+	}
+	first, last := re.Sub[:2], re.Sub[len(re.Sub)-2:]
+	if first[0].Op == syntax.OpBeginText {
+		if value, fold := longestLiteral(first[1]); !fold && first[1].Op == syntax.OpLiteral {
+			prefix = value
+		}
+	}
+	if last[1].Op == syntax.OpEndText {
+		if value, fold := longestLiteral(last[0]); !fold && last[0].Op == syntax.OpLiteral {
+			suffix = value
+		}
+	}
+	return prefix, suffix, literal, foldCase
+}
+
+func longestLiteral(re *syntax.Regexp) (literal string, foldCase bool) {
+
+	switch re.Op {
+	case syntax.OpLiteral:
+		// Literals without letters are matched the same way in case-insensitive expressions
+		if re.Flags&syntax.FoldCase != 0 && slices.ContainsFunc(re.Rune, func(r rune) bool { return unicode.SimpleFold(r) != r }) {
+			return string(re.Rune), true
+		}
+		return string(re.Rune), false
+	case syntax.OpCapture, syntax.OpPlus:
+		return longestLiteral(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Min > 0 {
+			return longestLiteral(re.Sub[0])
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			subLiteral, subFoldCase := longestLiteral(sub)
+			if len(subLiteral) > len(literal) || len(subLiteral) == len(literal) && foldCase && !subFoldCase {
+				literal, foldCase = subLiteral, subFoldCase
+			}
+		}
+	}
+	return literal, foldCase
+}
+
+// Map every rune of s to the smallest rune it is equal to under simple case folding, the folding
+// used by case-insensitive regular expressions. ASCII letters map to upper case
+func fold(s string) string {
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			if 'a' <= r && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			b.WriteByte(byte(r))
+			continue
+		}
+		if r == utf8.RuneError {
+			b.WriteRune(r)
+			continue
+		}
+		smallest := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			smallest = min(smallest, f)
+		}
+		b.WriteRune(smallest)
+	}
+	return b.String()
+}
+
+// Get a string with the bytes of s in reverse order
+func reverse(s string) string {
+
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+// Compiled matchers by the fingerprint of the rules they were compiled from
+var matchers = struct {
+	sync.Mutex
+	cache map[[sha256.Size]byte]*matcher
+}{cache: map[[sha256.Size]byte]*matcher{}}
+
+// Get the matcher for the rules of the EnvKeyMonitor objects, compiling it if the same rules
+// were not seen recently. Matchers only refer to rules by position, so they are shared by lists
+// holding the same rules in the same order
+func matcherFor(envKeyMonitors []*configv2.EnvKeyMonitor) *matcher {
+
+	hash := sha256.New()
+	for _, envKeyMonitor := range envKeyMonitors {
+		for _, rule := range envKeyMonitor.Spec.Rules {
+			hash.Write([]byte(rule.Match))
+			hash.Write([]byte{0})
+			hash.Write([]byte(rule.Name))
+			hash.Write([]byte{0})
+			hash.Write([]byte(rule.ValueRegex))
+			hash.Write([]byte{0})
+		}
+		hash.Write([]byte{1})
+	}
+	var fingerprint [sha256.Size]byte
+	hash.Sum(fingerprint[:0])
+
+	matchers.Lock()
+	m, ok := matchers.cache[fingerprint]
+	matchers.Unlock()
+	if ok {
+		return m
+	}
+
+	m = compile(envKeyMonitors)
+	matchers.Lock()
+	defer matchers.Unlock()
+	if len(matchers.cache) >= maxCachedMatchers {
+		clear(matchers.cache)
+	}
+	matchers.cache[fingerprint] = m
+	return m
+}
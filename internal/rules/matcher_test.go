@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// Generate monitors holding a mix of all match types, and a configmap of which some keys match
+func generate(random *rand.Rand, monitors, rulesPerMonitor, keys int) (*configv2.EnvKeyMonitorList, *corev1.ConfigMap) {
+
+	words := []string{"API", "AWS", "DB", "TOKEN", "SECRET", "KEY", "PASSWORD", "URL", "HOST", "PORT", "LOG", "LEVEL"}
+	word := func() string { return words[random.Intn(len(words))] }
+
+	envKeyMonitorList := &configv2.EnvKeyMonitorList{}
+	for i := range monitors {
+		envKeyMonitor := configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("monitor-%d", i), Namespace: "default"},
+		}
+		for j := range rulesPerMonitor {
+			rule := configv2.KeyRule{Name: fmt.Sprintf("%s_%s_%d", word(), word(), random.Intn(rulesPerMonitor*monitors))}
+			switch j % 20 {
+			case 0, 1, 2, 3:
+				rule.Match = configv2.MatchPrefix
+				rule.Name = fmt.Sprintf("%s_%d", word(), random.Intn(rulesPerMonitor))
+			case 4, 5, 6, 7:
+				rule.Match = configv2.MatchSuffix
+				rule.Name = fmt.Sprintf("_%s_%d", word(), random.Intn(rulesPerMonitor))
+			case 8, 9, 10:
+				rule.Match = configv2.MatchContains
+				rule.Name = fmt.Sprintf("%s%d", word(), random.Intn(rulesPerMonitor))
+			case 11:
+				rule.Match = configv2.MatchRegex
+				rule.Name = fmt.Sprintf("^%s_[0-9]+_%s$", word(), word())
+			case 12:
+				rule.Match = configv2.MatchRegex
+				rule.Name = fmt.Sprintf("(?i)%s.*%d$", word(), random.Intn(rulesPerMonitor))
+			default:
+				rule.Match = configv2.MatchExact
+			}
+			envKeyMonitor.Spec.Rules = append(envKeyMonitor.Spec.Rules, rule)
+		}
+		envKeyMonitorList.Items = append(envKeyMonitorList.Items, envKeyMonitor)
+	}
+
+	configmap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "configmap", Namespace: "default"},
+		Data:       map[string]string{},
+		BinaryData: map[string][]byte{},
+	}
+	for i := range keys {
+		key := fmt.Sprintf("%s_%s_%d", word(), word(), random.Intn(rulesPerMonitor*monitors))
+		if i%10 == 0 {
+			configmap.BinaryData[key] = []byte("value")
+			continue
+		}
+		configmap.Data[key] = "value"
+	}
+	return envKeyMonitorList, configmap
+}
+
+// Evaluate every rule against every key, as Find did before rules were compiled. A key matched by
+// several rules of an object is reported once, as Find does
+func findNaive(envKeyMonitorList *configv2.EnvKeyMonitorList, configmap *corev1.ConfigMap) []Violation {
+
+	fields := map[string][]string{
+		FieldData:       slices.Sorted(maps.Keys(configmap.Data)),
+		FieldBinaryData: slices.Sorted(maps.Keys(configmap.BinaryData)),
+	}
+	var violations []Violation
+	seen := map[string]int{}
+	for _, envKeyMonitor := range sortedByName(envKeyMonitorList) {
+		for _, rule := range envKeyMonitor.Spec.Rules {
+			for _, fieldName := range []string{FieldData, FieldBinaryData} {
+				for _, key := range fields[fieldName] {
+					if !Matches(rule, key) {
+						continue
+					}
+					violation := Violation{
+						Monitor:   envKeyMonitor,
+						Rule:      rule,
+						Field:     fieldName,
+						Key:       key,
+						ConfigMap: configmap.GetName(),
+						Namespace: configmap.GetNamespace(),
+					}
+					id := fmt.Sprintf("%s/%s/%s/%s", rule.Name, rule.Match, fieldName, key)
+					if i, ok := seen[id]; ok {
+						if rank(envKeyMonitor) > rank(violations[i].Monitor) {
+							violations[i] = violation
+						}
+						continue
+					}
+					seen[id] = len(violations)
+					violations = append(violations, violation)
+				}
+			}
+		}
+	}
+	return strictestRules(violations)
+}
+
+var _ = Describe("Matcher", func() {
+	It("Should find the same violations as evaluating every rule", func() {
+		random := rand.New(rand.NewSource(1))
+		for range 20 {
+			envKeyMonitorList, configmap := generate(random, 3, 200, 300)
+			violations := Find(envKeyMonitorList, configmap)
+			Expect(violations).NotTo(BeEmpty())
+			Expect(violations).To(Equal(findNaive(envKeyMonitorList, configmap)))
+		}
+	})
+
+	It("Should report every overlapping substring and anchored expression and ignore invalid expressions", func() {
+		envKeyMonitorList := &configv2.EnvKeyMonitorList{Items: []configv2.EnvKeyMonitor{{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{Rules: []configv2.KeyRule{
+				{Name: "SECRET", Match: configv2.MatchContains},
+				{Name: "CRET", Match: configv2.MatchContains},
+				{Name: "ECRETS", Match: configv2.MatchContains},
+				{Name: "SECRET_(", Match: configv2.MatchRegex},
+				{Name: "^MY_SEC", Match: configv2.MatchRegex},
+				{Name: "(?i)secrets$", Match: configv2.MatchRegex},
+				{Name: "^OTHER_", Match: configv2.MatchRegex},
+				{Name: "^[A-Z]+_.*S$", Match: configv2.MatchRegex},
+			}},
+		}}}
+		configmap := &corev1.ConfigMap{Data: map[string]string{"MY_SECRETS": "value", "OTHER": "value"}}
+
+		var rules []string
+		for _, key := range []string{"MY_SECRETS", "OTHER"} {
+			matcherFor(sortedByName(envKeyMonitorList)).match(key, func(e entry) {
+				rules = append(rules, envKeyMonitorList.Items[0].Spec.Rules[e.rule].Name)
+			})
+		}
+		Expect(rules).To(ConsistOf("SECRET", "CRET", "ECRETS", "^MY_SEC", "(?i)secrets$", "^[A-Z]+_.*S$"))
+
+		By("reporting the key once")
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Rule.Name).To(Equal("SECRET"))
+	})
+
+	It("Should evaluate expressions on their own if they cannot be combined", func() {
+		By("quoting the closing parenthesis of the combined program with a trailing \\Q")
+		envKeyMonitorList := &configv2.EnvKeyMonitorList{Items: []configv2.EnvKeyMonitor{{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{Rules: []configv2.KeyRule{
+				{Name: `[A-Z]+\Q`, Match: configv2.MatchRegex},
+				{Name: `^[a-z]+$`, Match: configv2.MatchRegex},
+			}},
+		}}}
+		configmap := &corev1.ConfigMap{Data: map[string]string{"TOKEN": "value", "token": "value", "1": "value"}}
+		Expect(func() { Find(envKeyMonitorList, configmap) }).NotTo(Panic())
+		Expect(Find(envKeyMonitorList, configmap)).To(Equal(findNaive(envKeyMonitorList, configmap)))
+		Expect(Find(envKeyMonitorList, configmap)).To(HaveLen(2))
+
+		By("exceeding the size of a single program")
+		envKeyMonitorList.Items[0].Spec.Rules = nil
+		for i := range 30 {
+			envKeyMonitorList.Items[0].Spec.Rules = append(envKeyMonitorList.Items[0].Spec.Rules, configv2.KeyRule{
+				Name:  fmt.Sprintf(`%s|^KEY_%d$`, strings.Repeat(`\pL`, 1000), i),
+				Match: configv2.MatchRegex,
+			})
+		}
+		configmap = &corev1.ConfigMap{Data: map[string]string{"KEY_7": "value", "KEY_70": "value"}}
+		violations := Find(envKeyMonitorList, configmap)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Key).To(Equal("KEY_7"))
+	})
+})
+
+// Admission latency with 10k rules and a configmap holding 1k keys. The matcher is compiled once
+// and reused, as it is for admission requests seeing the same rules
+func BenchmarkFind(b *testing.B) {
+	for _, rules := range []int{100, 1000, 10000} {
+		envKeyMonitorList, configmap := generate(rand.New(rand.NewSource(1)), 10, rules/10, 1000)
+		b.Run(fmt.Sprintf("rules=%d/keys=1000", rules), func(b *testing.B) {
+			for b.Loop() {
+				Find(envKeyMonitorList, configmap)
+			}
+		})
+	}
+}
+
+// Evaluating every rule against every key, for comparison with BenchmarkFind
+func BenchmarkFindNaive(b *testing.B) {
+	for _, rules := range []int{100, 1000} {
+		envKeyMonitorList, configmap := generate(rand.New(rand.NewSource(1)), 10, rules/10, 1000)
+		b.Run(fmt.Sprintf("rules=%d/keys=1000", rules), func(b *testing.B) {
+			for b.Loop() {
+				findNaive(envKeyMonitorList, configmap)
+			}
+		})
+	}
+}
+
+// Compiling the matcher, paid once per set of rules
+func BenchmarkCompile(b *testing.B) {
+	envKeyMonitorList, _ := generate(rand.New(rand.NewSource(1)), 10, 1000, 0)
+	envKeyMonitors := sortedByName(envKeyMonitorList)
+	for b.Loop() {
+		compile(envKeyMonitors)
+	}
+}
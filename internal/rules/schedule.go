@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"time"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// EnforcementStart returns the time the STRICT policy of an EnvKeyMonitor starts being enforced.
+// Without .spec.enforceAfter the policy is always enforced and the zero time is returned.
+// With maintenance windows, enforcement starts in the first window ending after enforceAfter.
+// ok is false if no such window exists, enforcement never starts in that case
+func EnforcementStart(envKeyMonitor *configv2.EnvKeyMonitor) (start time.Time, ok bool) {
+
+	if envKeyMonitor.Spec.EnforceAfter == nil {
+		return time.Time{}, true
+	}
+	enforceAfter := envKeyMonitor.Spec.EnforceAfter.Time
+	if len(envKeyMonitor.Spec.MaintenanceWindows) == 0 {
+		return enforceAfter, true
+	}
+
+	for _, window := range envKeyMonitor.Spec.MaintenanceWindows {
+		if !window.End.After(enforceAfter) {
+			continue
+		}
+		candidate := enforceAfter
+		if window.Start.After(enforceAfter) {
+			candidate = window.Start.Time
+		}
+		if !ok || candidate.Before(start) {
+			start, ok = candidate, true
+		}
+	}
+	return start, ok
+}
+
+// Enforcing returns true if the STRICT policy of an EnvKeyMonitor is enforced at the given time
+func Enforcing(envKeyMonitor *configv2.EnvKeyMonitor, now time.Time) bool {
+
+	start, ok := EnforcementStart(envKeyMonitor)
+	return ok && !now.Before(start)
+}
+
+// Countdown describes when the STRICT policy of an EnvKeyMonitor that is not enforced yet starts
+// being enforced, e.g. "Enforcement starts at 2026-12-01T00:00:00Z, in 12d 3h 4m"
+func Countdown(envKeyMonitor *configv2.EnvKeyMonitor, now time.Time) string {
+
+	start, ok := EnforcementStart(envKeyMonitor)
+	if !ok {
+		return fmt.Sprintf(
+			"Enforcement is scheduled after %s, but no maintenance window ends after that time",
+			envKeyMonitor.Spec.EnforceAfter.UTC().Format(time.RFC3339),
+		)
+	}
+
+	remaining := start.Sub(now).Round(time.Minute)
+	if remaining < time.Minute {
+		remaining = time.Minute
+	}
+	days := int(remaining / (24 * time.Hour))
+	hours := int(remaining % (24 * time.Hour) / time.Hour)
+	minutes := int(remaining % time.Hour / time.Minute)
+
+	return fmt.Sprintf(
+		"Enforcement starts at %s, in %dd %dh %dm",
+		start.UTC().Format(time.RFC3339),
+		days,
+		hours,
+		minutes,
+	)
+}
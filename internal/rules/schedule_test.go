@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("Schedule", func() {
+	var (
+		envKeyMonitor *configv2.EnvKeyMonitor
+		enforceAfter  time.Time
+	)
+
+	BeforeEach(func() {
+		enforceAfter = time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+		envKeyMonitor = &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Policy:       configv2.PolicyStrict,
+				EnforceAfter: &metav1.Time{Time: enforceAfter},
+			},
+		}
+	})
+
+	It("Should always enforce objects without enforceAfter", func() {
+		envKeyMonitor.Spec.EnforceAfter = nil
+		Expect(Enforcing(envKeyMonitor, time.Time{})).To(BeTrue())
+	})
+
+	It("Should enforce from enforceAfter and count down until then", func() {
+		Expect(Enforcing(envKeyMonitor, enforceAfter.Add(-time.Second))).To(BeFalse())
+		Expect(Enforcing(envKeyMonitor, enforceAfter)).To(BeTrue())
+		Expect(Countdown(envKeyMonitor, enforceAfter.Add(-(49*time.Hour + 30*time.Minute)))).To(
+			Equal("Enforcement starts at 2026-12-01T00:00:00Z, in 2d 1h 30m"),
+		)
+	})
+
+	It("Should start enforcement in the first maintenance window ending after enforceAfter", func() {
+		envKeyMonitor.Spec.MaintenanceWindows = []configv2.MaintenanceWindow{
+			{Start: metav1.NewTime(enforceAfter.Add(72 * time.Hour)), End: metav1.NewTime(enforceAfter.Add(74 * time.Hour))},
+			{Start: metav1.NewTime(enforceAfter.Add(-48 * time.Hour)), End: metav1.NewTime(enforceAfter.Add(-46 * time.Hour))},
+			{Start: metav1.NewTime(enforceAfter.Add(24 * time.Hour)), End: metav1.NewTime(enforceAfter.Add(26 * time.Hour))},
+		}
+		start, ok := EnforcementStart(envKeyMonitor)
+		Expect(ok).To(BeTrue())
+		Expect(start).To(Equal(enforceAfter.Add(24 * time.Hour)))
+
+		By("starting at enforceAfter if it lies within a window")
+		envKeyMonitor.Spec.MaintenanceWindows[1].End = metav1.NewTime(enforceAfter.Add(time.Hour))
+		start, ok = EnforcementStart(envKeyMonitor)
+		Expect(ok).To(BeTrue())
+		Expect(start).To(Equal(enforceAfter))
+
+		By("never starting if all windows ended before enforceAfter")
+		envKeyMonitor.Spec.MaintenanceWindows = envKeyMonitor.Spec.MaintenanceWindows[1:2]
+		envKeyMonitor.Spec.MaintenanceWindows[0].End = metav1.NewTime(enforceAfter.Add(-time.Hour))
+		Expect(Enforcing(envKeyMonitor, enforceAfter.Add(time.Hour))).To(BeFalse())
+		Expect(Countdown(envKeyMonitor, enforceAfter)).To(ContainSubstring("no maintenance window"))
+	})
+})
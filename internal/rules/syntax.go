@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// Characters allowed in a ConfigMap key, as checked by validation.IsConfigMapKey
+var keyFragment = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// SyntaxErrors checks if the name of a rule can match a ConfigMap key. Exact rules must be a
+// valid ConfigMap key, prefixes, suffixes and substrings may only hold characters allowed in
+// keys. Rules matching by regular expression are patterns and checked when compiled instead
+func SyntaxErrors(rule configv2.KeyRule) []string {
+
+	if rule.Match == configv2.MatchRegex {
+		return nil
+	}
+	if strings.TrimSpace(rule.Name) != rule.Name {
+		return []string{"must not start or end with whitespace, it would never match a ConfigMap key"}
+	}
+
+	switch rule.Match {
+	case configv2.MatchPrefix, configv2.MatchSuffix, configv2.MatchContains:
+		var errs []string
+		if len(rule.Name) > validation.DNS1123SubdomainMaxLength {
+			errs = append(errs, validation.MaxLenError(validation.DNS1123SubdomainMaxLength))
+		}
+		if !keyFragment.MatchString(rule.Name) {
+			errs = append(errs, fmt.Sprintf(
+				"a %s of a config key must consist of alphanumeric characters, '-', '_' or '.', "+
+					"use match Regex for patterns",
+				fragmentName(rule.Match),
+			))
+		}
+		return errs
+	default:
+		return validation.IsConfigMapKey(rule.Name)
+	}
+}
+
+// Get how the name of a rule is described in errors
+func fragmentName(match configv2.MatchType) string {
+
+	switch match {
+	case configv2.MatchPrefix:
+		return "prefix"
+	case configv2.MatchSuffix:
+		return "suffix"
+	default:
+		return "substring"
+	}
+}
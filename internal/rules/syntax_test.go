@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("Syntax", func() {
+	It("Should accept names that can match a ConfigMap key", func() {
+		for _, rule := range []configv2.KeyRule{
+			{Name: "API_KEY"},
+			{Name: "app.properties", Match: configv2.MatchExact},
+			{Name: "AWS_", Match: configv2.MatchPrefix},
+			{Name: ".json", Match: configv2.MatchSuffix},
+			{Name: ".", Match: configv2.MatchContains},
+			{Name: "^ DB (", Match: configv2.MatchRegex},
+		} {
+			Expect(SyntaxErrors(rule)).To(BeEmpty(), rule.Name)
+		}
+	})
+
+	It("Should reject names that never match a ConfigMap key", func() {
+		for _, rule := range []configv2.KeyRule{
+			{Name: "API_KEY "},
+			{Name: " AWS_", Match: configv2.MatchPrefix},
+			{Name: "API KEY"},
+			{Name: ".."},
+			{Name: "API$KEY"},
+			{Name: "TOKEN*", Match: configv2.MatchSuffix},
+			{Name: "SECRET/", Match: configv2.MatchContains},
+			{Name: strings.Repeat("A", 254), Match: configv2.MatchPrefix},
+		} {
+			Expect(SyntaxErrors(rule)).NotTo(BeEmpty(), rule.Name)
+		}
+		Expect(SyntaxErrors(configv2.KeyRule{Name: "AWS_*", Match: configv2.MatchPrefix})).To(
+			ConsistOf(ContainSubstring("a prefix of a config key")),
+		)
+	})
+})
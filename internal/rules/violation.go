@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rules evaluates ConfigMaps against the rules of EnvKeyMonitor objects.
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+// DefaultMessage is used when neither the violated rule nor its EnvKeyMonitor define a message
+const DefaultMessage = "Configmap contains forbidden key and is therefore invalid. Forbidden key is '{{ .Key }}'"
+
+// DefaultUnapprovedMessage is used for unapproved keys when their Allowlist EnvKeyMonitor does not
+// define a message
+const DefaultUnapprovedMessage = "Configmap contains key that is not approved and is therefore invalid. " +
+	"Unapproved key is '{{ .Key }}'"
+
+// DefaultKeyNameMessage is used for keys not following the key name policy when their EnvKeyMonitor
+// does not define a message. It is followed by the reason the key does not follow the policy
+const DefaultKeyNameMessage = "Configmap contains key not following the naming convention and is therefore invalid. " +
+	"Key '{{ .Key }}' is invalid"
+
+// DefaultMissingKeyMessage is used for required keys missing from a ConfigMap when their
+// EnvKeyMonitor does not define a message
+const DefaultMissingKeyMessage = "Configmap is missing a required key and is therefore invalid. " +
+	"Missing key is '{{ .Key }}'"
+
+// Number of bytes a rendered message or docs URL is cut to
+const maxRenderedLength = 2048
+
+// variable matches a variable of a message or docs URL template, e.g. '{{ .Key }}'
+var variable = regexp.MustCompile(`\{\{\s*\.(\w+)\s*\}\}`)
+
+// TemplateData holds the variables available to message and docsURL templates
+type TemplateData struct {
+	Key       string
+	ConfigMap string
+	Namespace string
+	Monitor   string
+}
+
+// Violation describes a ConfigMap key matched by a rule of an EnvKeyMonitor, or a key reported by
+// an EnvKeyMonitor for another reason described by Kind
+type Violation struct {
+	Monitor *configv2.EnvKeyMonitor
+	// Rule is the zero value for violations of another kind
+	Rule configv2.KeyRule
+	// Field is the ConfigMap field holding the key, either "data" or "binaryData". Missing keys
+	// are reported under "data"
+	Field     string
+	Key       string
+	ConfigMap string
+	Namespace string
+	// Kind is empty for keys matched by a rule
+	Kind configv2.ViolationKind
+	// Detail describes why a key does not follow the key name policy
+	Detail string
+}
+
+// Action returns the action taken on the violation: the entry of .spec.severityPolicy matching the
+// severity of the violated rule, or the action following from .spec.policy
+func (v Violation) Action() configv2.SeverityAction {
+
+	if v.Rule.Severity != "" {
+		for _, severityPolicy := range v.Monitor.Spec.SeverityPolicy {
+			if severityPolicy.Severity == v.Rule.Severity {
+				return severityPolicy.Action
+			}
+		}
+	}
+	if v.Monitor.Spec.Policy == configv2.PolicyStrict {
+		return configv2.SeverityActionDeny
+	}
+	return configv2.SeverityActionWarn
+}
+
+// Message renders the message of the violated rule, falling back to the message of
+// the EnvKeyMonitor and DefaultMessage
+func (v Violation) Message() string {
+
+	for _, text := range []string{v.Rule.Message, v.Monitor.Spec.Message} {
+		if text == "" {
+			continue
+		}
+		// Templates are validated when the EnvKeyMonitor is admitted
+		if message, err := render(text, v.templateData()); err == nil {
+			return message
+		}
+	}
+	switch v.Kind {
+	case configv2.ViolationKindUnapprovedKey:
+		message, _ := render(DefaultUnapprovedMessage, v.templateData())
+		return message
+	case configv2.ViolationKindMissingKey:
+		message, _ := render(DefaultMissingKeyMessage, v.templateData())
+		return message
+	case configv2.ViolationKindKeyName:
+		message, _ := render(DefaultKeyNameMessage, v.templateData())
+		if v.Detail != "" {
+			message += ", " + v.Detail
+		}
+		return message
+	}
+	message, _ := render(DefaultMessage, v.templateData())
+	return message
+}
+
+// DocsURL renders the docs URL of the violated rule, falling back to the docs URL of the EnvKeyMonitor
+func (v Violation) DocsURL() string {
+
+	for _, text := range []string{v.Rule.DocsURL, v.Monitor.Spec.DocsURL} {
+		if text == "" {
+			continue
+		}
+		if docsURL, err := render(text, v.templateData()); err == nil {
+			return docsURL
+		}
+	}
+	return ""
+}
+
+// String returns the rendered message followed by the rendered docs URL
+func (v Violation) String() string {
+
+	message := v.Message()
+	if docsURL := v.DocsURL(); docsURL != "" {
+		message += ". See " + docsURL
+	}
+	return message
+}
+
+// Path returns the path of the key in the ConfigMap
+func (v Violation) Path() *field.Path {
+	return field.NewPath(v.Field).Key(v.Key)
+}
+
+func (v Violation) id() string {
+	return strings.Join([]string{v.Monitor.GetName(), string(v.Kind), v.Rule.Name, v.Rule.ValueRegex, v.Field, v.Key}, "/")
+}
+
+func (v Violation) templateData() TemplateData {
+	return TemplateData{
+		Key:       v.Key,
+		ConfigMap: v.ConfigMap,
+		Namespace: v.Namespace,
+		Monitor:   v.Monitor.GetName(),
+	}
+}
+
+// ValidateTemplate checks that text can be rendered as a message or docs URL
+func ValidateTemplate(text string) error {
+
+	_, err := render(text, TemplateData{})
+	return err
+}
+
+// render substitutes the variables of a message or docs URL. Templates only hold variables, they
+// are written by tenants and must not run actions or functions
+func render(text string, data TemplateData) (string, error) {
+
+	// Check for template actions other than variables, e.g. '{{ printf ... }}'
+	if strings.Contains(variable.ReplaceAllString(text, ""), "{{") {
+		return "", fmt.Errorf("Only the variables .Key, .ConfigMap, .Namespace and .Monitor can be used")
+	}
+	values := map[string]string{
+		"Key":       data.Key,
+		"ConfigMap": data.ConfigMap,
+		"Namespace": data.Namespace,
+		"Monitor":   data.Monitor,
+	}
+	var err error
+	rendered := variable.ReplaceAllStringFunc(text, func(match string) string {
+		name := variable.FindStringSubmatch(match)[1]
+		value, ok := values[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("Unknown variable .%s, only .Key, .ConfigMap, .Namespace and .Monitor can be used", name)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Cut long messages without splitting a character
+	if len(rendered) > maxRenderedLength {
+		rendered = rendered[:maxRenderedLength]
+		for !utf8.ValidString(rendered) {
+			rendered = rendered[:len(rendered)-1]
+		}
+	}
+	return rendered, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("Violation", func() {
+	var violation Violation
+
+	BeforeEach(func() {
+		violation = Violation{
+			Monitor: &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "team-a"},
+			},
+			Rule:      configv2.KeyRule{Name: "API_KEY"},
+			Key:       "API_KEY",
+			ConfigMap: "app-config",
+			Namespace: "team-a",
+		}
+	})
+
+	It("Should render the default message", func() {
+		Expect(violation.String()).To(Equal(
+			"Configmap contains forbidden key and is therefore invalid. Forbidden key is 'API_KEY'",
+		))
+	})
+
+	It("Should render the message of the EnvKeyMonitor", func() {
+		violation.Monitor.Spec.Message = "Move {{ .Key }} of {{ .Namespace }}/{{ .ConfigMap }} to Vault ({{ .Monitor }})"
+		violation.Monitor.Spec.DocsURL = "https://wiki.example.com/secrets#{{ .Key }}"
+		Expect(violation.String()).To(Equal(
+			"Move API_KEY of team-a/app-config to Vault (monitor). See https://wiki.example.com/secrets#API_KEY",
+		))
+	})
+
+	It("Should prefer the message of the rule", func() {
+		violation.Monitor.Spec.Message = "monitor message"
+		violation.Monitor.Spec.DocsURL = "https://wiki.example.com/monitor"
+		violation.Rule.Message = "rule message for {{ .Key }}"
+		violation.Rule.DocsURL = "https://wiki.example.com/rule"
+		Expect(violation.String()).To(Equal("rule message for API_KEY. See https://wiki.example.com/rule"))
+	})
+
+	It("Should fall back if a template cannot be rendered", func() {
+		violation.Rule.Message = "{{ .Team }}"
+		violation.Monitor.Spec.Message = "monitor message"
+		Expect(violation.Message()).To(Equal("monitor message"))
+	})
+
+	It("Should take the action of the severity policy before the policy", func() {
+		Expect(violation.Action()).To(Equal(configv2.SeverityActionWarn))
+		violation.Monitor.Spec.Policy = configv2.PolicyStrict
+		Expect(violation.Action()).To(Equal(configv2.SeverityActionDeny))
+
+		violation.Monitor.Spec.SeverityPolicy = []configv2.SeverityPolicy{
+			{Severity: configv2.SeverityLow, Action: configv2.SeverityActionAudit},
+		}
+		violation.Rule.Severity = configv2.SeverityLow
+		Expect(violation.Action()).To(Equal(configv2.SeverityActionAudit))
+
+		By("following the policy for severities not listed")
+		violation.Rule.Severity = configv2.SeverityHigh
+		Expect(violation.Action()).To(Equal(configv2.SeverityActionDeny))
+	})
+
+	It("Should validate templates", func() {
+		Expect(ValidateTemplate("{{ .Key }} {{ .ConfigMap }} {{ .Namespace }} {{ .Monitor }}")).To(Succeed())
+		Expect(ValidateTemplate("{{ .Key")).NotTo(Succeed())
+		Expect(ValidateTemplate("{{ .Team }}")).NotTo(Succeed())
+		Expect(ValidateTemplate(`{{ printf "%01000000d" 1 }}`)).NotTo(Succeed())
+		Expect(ValidateTemplate("{{ if .Key }}{{ .Key }}{{ end }}")).NotTo(Succeed())
+	})
+
+	It("Should only substitute variables and cut long messages", func() {
+		violation.Monitor.Spec.Message = "{{.Key}} in {{ .Namespace }}/{{  .ConfigMap }} is {{ .Key }}"
+		Expect(violation.Message()).To(Equal(violation.Key + " in " + violation.Namespace + "/" + violation.ConfigMap +
+			" is " + violation.Key))
+
+		violation.Monitor.Spec.Message = strings.Repeat("{{ .Key }}", 1000)
+		Expect(violation.Message()).To(HaveLen(maxRenderedLength))
+	})
+})
@@ -18,18 +18,31 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/netip"
+	"slices"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
-	configv1 "github.com/Nivesh00/config-keys-operator.git/api/v1"
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/audit"
+	"github.com/Nivesh00/config-keys-operator.git/internal/keysets"
+	"github.com/Nivesh00/config-keys-operator.git/internal/metrics"
+	"github.com/Nivesh00/config-keys-operator.git/internal/notify"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
 )
 
 // nolint:unused
@@ -37,11 +50,29 @@ import (
 var configmaplog = logf.Log.WithName("configmap-resource")
 
 // SetupConfigMapWebhookWithManager registers the webhook for ConfigMap in the manager.
-func SetupConfigMapWebhookWithManager(mgr ctrl.Manager) error {
+// The notifier delivering violations to NotificationTarget objects and the recorder writing the status
+// of EnvKeyMonitor objects are added to the manager as well.
+// Admission decisions are written to auditLog, which may be nil. Notifications are only sent to
+// public addresses and addresses in allowedNetworks
+func SetupConfigMapWebhookWithManager(mgr ctrl.Manager, auditLog *audit.Logger, allowedNetworks []netip.Prefix) error {
+
+	notifier := notify.NewNotifier(mgr.GetClient(), mgr.GetAPIReader(), allowedNetworks)
+	if err := mgr.Add(notifier); err != nil {
+		return err
+	}
+
+	statusRecorder := NewStatusRecorder(mgr.GetClient())
+	if err := mgr.Add(statusRecorder); err != nil {
+		return err
+	}
 
 	return ctrl.NewWebhookManagedBy(mgr).For(&corev1.ConfigMap{}).
 		WithValidator(&ConfigMapCustomValidator{
-			mgr.GetClient(),
+			Client:         mgr.GetClient(),
+			Recorder:       mgr.GetEventRecorderFor("configmap-webhook"),
+			Notifier:       notifier,
+			AuditLog:       auditLog,
+			StatusRecorder: statusRecorder,
 		}).
 		WithValidatorCustomPath("/env-keys-validation").
 		Complete()
@@ -49,9 +80,16 @@ func SetupConfigMapWebhookWithManager(mgr ctrl.Manager) error {
 
 // TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
+// Deletes are only audited and never rejected, they are sent to a separate webhook that is ignored
+// when the webhook server is unavailable.
 // NOTE: If you want to customise the 'path', use the flags '--defaulting-path' or '--validation-path'.
-// +kubebuilder:webhook:path=/env-keys-validation,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=configmaps,verbs=create;update,versions=v1,name=vconfigmap-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeymonitors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=envkeysets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=notificationtargets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.core.nvsh-ram.io,resources=notificationtargets/status,verbs=get;update;patch
+// +kubebuilder:webhook:path=/env-keys-validation,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups="",resources=configmaps,verbs=create;update,versions=v1,name=vconfigmap-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/env-keys-validation,mutating=false,failurePolicy=ignore,sideEffects=NoneOnDryRun,groups="",resources=configmaps,verbs=delete,versions=v1,name=vconfigmap-delete-v1.kb.io,admissionReviewVersions=v1
 
 // ConfigMapCustomValidator struct is responsible for validating the ConfigMap resource
 // when it is created, updated, or deleted.
@@ -63,6 +101,14 @@ type ConfigMapCustomValidator struct {
 	// TODO(user): Add more fields as needed for validation
 	// Used to query k8s
 	client.Client
+	// Used to report violations on the EnvKeyMonitor objects
+	Recorder record.EventRecorder
+	// Used to deliver violations to NotificationTarget objects, optional
+	Notifier *notify.Notifier
+	// Used to record every admission decision, optional
+	AuditLog *audit.Logger
+	// Used to record shadow denials and admitting users in the status of EnvKeyMonitor objects, optional
+	StatusRecorder *StatusRecorder
 }
 
 var _ webhook.CustomValidator = &ConfigMapCustomValidator{}
@@ -74,34 +120,47 @@ func (v *ConfigMapCustomValidator) ValidateCreate(ctx context.Context, obj runti
 		return nil, fmt.Errorf("expected a ConfigMap object but got %T", obj)
 	}
 	configmaplog.Info("Validation for ConfigMap upon creation", "name", configmap.GetName())
+	start := time.Now()
 
 	// (user): fill in your validation logic upon object creation.
 
 	// Get list of existing EnvKeyMonitors
-	var envKeyMonitorList configv1.EnvKeyMonitorList
+	var envKeyMonitorList configv2.EnvKeyMonitorList
 	if err := v.List(ctx, &envKeyMonitorList, client.InNamespace(configmap.Namespace)); err != nil {
 		configmaplog.Info(err.Error() + " Cannot get EnvKeyMonitor CRDs in namespace. Rejecting configmap creation")
-		return nil, fmt.Errorf("failed to list configmaps: %v", err)
+		err = fmt.Errorf("failed to list envkeymonitors: %v", err)
+		v.auditDecision(ctx, start, configmap, nil, nil, err)
+		return nil, err
+	}
+	// Evaluate the rules of presets and referenced EnvKeySet objects as well
+	if err := keysets.Resolve(ctx, v.Client, &envKeyMonitorList); err != nil {
+		configmaplog.Info(err.Error() + " Cannot get EnvKeySet objects. Rejecting configmap")
+		err = fmt.Errorf("failed to list keysets: %v", err)
+		v.auditDecision(ctx, start, configmap, nil, nil, err)
+		return nil, err
 	}
 
 	// Get all forbidden keys
-	forbiddenKeysList := getEnvKevMonitorKeys(&envKeyMonitorList)
+	forbiddenRulesList := getEnvKeyMonitorRules(&envKeyMonitorList)
 
 	configmaplog.Info("Configmap which contain the following keys are not allowed in the current namespace",
 		"namespace",
 		configmap.Namespace,
 		"forbidden keys",
-		strings.Join(*forbiddenKeysList, ", "),
+		strings.Join(ruleNames(forbiddenRulesList), ", "),
 	)
 
 	// Check if configmap contains a forbidden key
 	configmaplog.Info("Checking if configmap contains forbidden keys...")
-	if err := checkConfigmapKeys(forbiddenKeysList, configmap); err != nil {
+	violations := findViolations(&envKeyMonitorList, configmap, requesterFromContext(ctx))
+	warnings, err := v.checkConfigmapKeys(ctx, &envKeyMonitorList, violations, nil, configmap)
+	v.auditDecision(ctx, start, configmap, violations, warnings, err)
+	if err != nil {
 		configmaplog.Info(err.Error() + " rejecting configmap...")
-		return nil, err
+		return warnings, err
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ConfigMap.
@@ -110,35 +169,52 @@ func (v *ConfigMapCustomValidator) ValidateUpdate(ctx context.Context, oldObj, n
 	if !ok {
 		return nil, fmt.Errorf("expected a ConfigMap object for the newObj but got %T", newObj)
 	}
+	oldConfigmap, ok := oldObj.(*corev1.ConfigMap)
+	if !ok {
+		return nil, fmt.Errorf("expected a ConfigMap object for the oldObj but got %T", oldObj)
+	}
 	configmaplog.Info("Validation for ConfigMap upon update", "name", configmap.GetName())
+	start := time.Now()
 
 	// TODO(user): fill in your validation logic upon object update.
 
 	// Get list of existing EnvKeyMonitors
-	var envKeyMonitorList configv1.EnvKeyMonitorList
+	var envKeyMonitorList configv2.EnvKeyMonitorList
 	if err := v.List(ctx, &envKeyMonitorList, client.InNamespace(configmap.Namespace)); err != nil {
 		configmaplog.Info(err.Error() + " Cannot get EnvKeyMonitor CRDs in namespace. Rejecting configmap creation")
-		return nil, fmt.Errorf("failed to list configmaps: %v", err)
+		err = fmt.Errorf("failed to list envkeymonitors: %v", err)
+		v.auditDecision(ctx, start, configmap, nil, nil, err)
+		return nil, err
+	}
+	// Evaluate the rules of presets and referenced EnvKeySet objects as well
+	if err := keysets.Resolve(ctx, v.Client, &envKeyMonitorList); err != nil {
+		configmaplog.Info(err.Error() + " Cannot get EnvKeySet objects. Rejecting configmap")
+		err = fmt.Errorf("failed to list keysets: %v", err)
+		v.auditDecision(ctx, start, configmap, nil, nil, err)
+		return nil, err
 	}
 
 	// Get all forbidden keys
-	forbiddenKeysList := getEnvKevMonitorKeys(&envKeyMonitorList)
+	forbiddenRulesList := getEnvKeyMonitorRules(&envKeyMonitorList)
 
 	configmaplog.Info("Configmap which contain the following keys are not allowed in the current namespace",
 		"namespace",
 		configmap.Namespace,
 		"forbidden keys",
-		strings.Join(*forbiddenKeysList, ", "),
+		strings.Join(ruleNames(forbiddenRulesList), ", "),
 	)
 
 	// Check if configmap contains a forbidden key
 	configmaplog.Info("Checking if configmap contains forbidden keys...")
-	if err := checkConfigmapKeys(forbiddenKeysList, configmap); err != nil {
+	violations := findViolations(&envKeyMonitorList, configmap, requesterFromContext(ctx))
+	warnings, err := v.checkConfigmapKeys(ctx, &envKeyMonitorList, violations, oldConfigmap, configmap)
+	v.auditDecision(ctx, start, configmap, violations, warnings, err)
+	if err != nil {
 		configmaplog.Info(err.Error() + " rejecting configmap...")
-		return nil, err
+		return warnings, err
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ConfigMap.
@@ -148,35 +224,344 @@ func (v *ConfigMapCustomValidator) ValidateDelete(ctx context.Context, obj runti
 		return nil, fmt.Errorf("expected a ConfigMap object but got %T", obj)
 	}
 	configmaplog.Info("Validation for ConfigMap upon deletion", "name", configmap.GetName())
+	start := time.Now()
 
 	// TODO(user): fill in your validation logic upon object deletion.
 
+	// Get list of existing EnvKeyMonitors, deletion is never rejected
+	var envKeyMonitorList configv2.EnvKeyMonitorList
+	if err := v.List(ctx, &envKeyMonitorList, client.InNamespace(configmap.Namespace)); err != nil {
+		configmaplog.Info(err.Error() + " Cannot get EnvKeyMonitor CRDs in namespace. Skipping audit of configmap deletion")
+		v.auditDecision(ctx, start, configmap, nil, nil, nil)
+		return nil, nil
+	}
+	if err := keysets.Resolve(ctx, v.Client, &envKeyMonitorList); err != nil {
+		configmaplog.Info(err.Error() + " Cannot get EnvKeySet objects. Only auditing the rules of EnvKeyMonitors")
+	}
+
+	// Audit the violations resolved by the deletion
+	violations := v.auditConfigmapDelete(ctx, &envKeyMonitorList, configmap)
+	v.auditDecision(ctx, start, configmap, violations, nil, nil)
+
 	return nil, nil
 }
 
-// Get a list of all EnvKeyMonitor keys in namespace
-func getEnvKevMonitorKeys(envKeyMonitorList *configv1.EnvKeyMonitorList) *[]string {
+// Record the forbidden keys of a deleted configmap as resolved by delete, on every EnvKeyMonitor
+// holding a matching rule. Returns the resolved violations
+func (v *ConfigMapCustomValidator) auditConfigmapDelete(
+	ctx context.Context,
+	envKeyMonitorList *configv2.EnvKeyMonitorList,
+	configmap *corev1.ConfigMap,
+) []rules.Violation {
+
+	var resolved []rules.Violation
+	for i := range envKeyMonitorList.Items {
+		singleList := &configv2.EnvKeyMonitorList{Items: envKeyMonitorList.Items[i : i+1]}
+		for _, violation := range rules.Find(singleList, configmap) {
+			configmaplog.Info(
+				"Violation resolved by deleting configmap",
+				"configmap",
+				configmap.GetName(),
+				"namespace",
+				configmap.GetNamespace(),
+				"monitor",
+				violation.Monitor.GetName(),
+				"rule",
+				violation.Rule.Name,
+				"key",
+				violation.Key,
+			)
+			v.recordViolation(
+				ctx,
+				violation,
+				corev1.EventTypeNormal,
+				"ViolationResolvedByDelete",
+				fmt.Sprintf("forbidden key '%s' was removed by deleting the configmap", violation.Key),
+			)
+			resolved = append(resolved, violation)
+		}
+	}
+	return resolved
+}
+
+// Get a list of all EnvKeyMonitor rules in namespace
+func getEnvKeyMonitorRules(envKeyMonitorList *configv2.EnvKeyMonitorList) *[]configv2.KeyRule {
+
+	var allRules []configv2.KeyRule
+	for _, envKeyMonitor := range envKeyMonitorList.Items {
+		allRules = append(allRules, envKeyMonitor.Spec.Rules...)
+	}
+	return &allRules
+}
+
+// Get the names of all rules
+func ruleNames(rules *[]configv2.KeyRule) []string {
+
+	names := make([]string, 0, len(*rules))
+	for _, rule := range *rules {
+		names = append(names, rule.Name)
+	}
+	return names
+}
+
+// Check if EnvKeyMonitor CRD in current namespace contain a key. Keys forbidden by a STRICT
+// EnvKeyMonitor reject the configmap, keys forbidden by a PERMISSIVE EnvKeyMonitor only warn.
+// .spec.severityPolicy overrides the policy for rules of the listed severities.
+// STRICT EnvKeyMonitors with scheduled enforcement only warn until enforcement starts.
+// Subjects listed in .spec.bypass are admitted with a warning instead of being denied.
+// All rejected keys are reported at once, each pointing at its path in the configmap.
+// violations are the violations found in configmap.
+// On update, oldConfigmap is used to only enforce the keys selected by .spec.enforceOn
+func (v *ConfigMapCustomValidator) checkConfigmapKeys(
+	ctx context.Context,
+	envKeyMonitorList *configv2.EnvKeyMonitorList,
+	violations []rules.Violation,
+	oldConfigmap *corev1.ConfigMap,
+	configmap *corev1.ConfigMap,
+) (admission.Warnings, error) {
+
+	reducesViolations := oldConfigmap != nil && rules.Reduces(
+		findViolations(envKeyMonitorList, oldConfigmap, requesterFromContext(ctx)),
+		violations,
+	)
+
+	var warnings admission.Warnings
+	var errs field.ErrorList
+	var shadowDenied, admitted []rules.Violation
+	for _, violation := range violations {
+		message := violation.String()
+		mode := rules.ModeOf(violation.Monitor)
+		action := violation.Action()
+
+		// Audit only records the violation
+		if mode == configv2.ModeAudit || action == configv2.SeverityActionAudit {
+			v.recordViolation(ctx, violation, corev1.EventTypeWarning, "ForbiddenKeyAudited", message)
+			v.reportViolation(ctx, violation, metrics.ActionAudited)
+			admitted = append(admitted, violation)
+			continue
+		}
+
+		if action == configv2.SeverityActionDeny &&
+			isEnforced(violation, oldConfigmap, configmap, reducesViolations) {
+			// Scheduled enforcement warns with a countdown until it starts
+			if now := time.Now(); !rules.Enforcing(violation.Monitor, now) {
+				message = fmt.Sprintf("%s. %s", message, rules.Countdown(violation.Monitor, now))
+				v.recordViolation(ctx, violation, corev1.EventTypeWarning, "ForbiddenKeyAdmitted", message)
+				v.reportViolation(ctx, violation, metrics.ActionWarned)
+				warnings = append(warnings, message)
+				admitted = append(admitted, violation)
+				continue
+			}
+			// Shadow records what would have been denied and admits the configmap
+			if mode == configv2.ModeShadow {
+				v.recordViolation(ctx, violation, corev1.EventTypeWarning, "ForbiddenKeyShadowDenied", message)
+				v.reportViolation(ctx, violation, metrics.ActionShadowDenied)
+				shadowDenied = append(shadowDenied, violation)
+				admitted = append(admitted, violation)
+				continue
+			}
+			// Subjects listed in .spec.bypass are admitted with a warning
+			if requester := requesterFromContext(ctx); rules.Bypasses(violation.Monitor, requester) {
+				message = fmt.Sprintf("%s. Denial bypassed for %s", message, describeRequester(requester))
+				v.recordViolation(ctx, violation, corev1.EventTypeWarning, "ForbiddenKeyBypassed", message)
+				v.reportViolation(ctx, violation, metrics.ActionBypassed)
+				warnings = append(warnings, message)
+				admitted = append(admitted, violation)
+				continue
+			}
+			v.recordViolation(ctx, violation, corev1.EventTypeWarning, "ForbiddenKeyDenied", message)
+			v.reportViolation(ctx, violation, metrics.ActionDenied)
+			if violation.Kind == configv2.ViolationKindMissingKey {
+				errs = append(errs, field.Required(violation.Path(), message))
+				continue
+			}
+			errs = append(errs, field.Forbidden(violation.Path(), message))
+			continue
+		}
+		v.recordViolation(ctx, violation, corev1.EventTypeWarning, "ForbiddenKeyAdmitted", message)
+		v.reportViolation(ctx, violation, metrics.ActionWarned)
+		warnings = append(warnings, message)
+		admitted = append(admitted, violation)
+	}
+	if len(errs) == 0 {
+		// Only admitted configmaps are stored, the status is written in the background
+		v.StatusRecorder.Record(ctx, shadowDenied, admitted)
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(
+		corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind(),
+		configmap.GetName(),
+		errs,
+	)
+}
 
-	var allKeys []string
+// Find the violations of a configmap. The rules of EnvKeyMonitors bypassing the requester only
+// report keys not matched by the same rule of another EnvKeyMonitor, so a bypass never
+// suppresses the denial of an object not listing the requester
+func findViolations(
+	envKeyMonitorList *configv2.EnvKeyMonitorList,
+	configmap *corev1.ConfigMap,
+	requester *configv2.Requester,
+) []rules.Violation {
+
+	enforced := &configv2.EnvKeyMonitorList{}
+	bypassing := &configv2.EnvKeyMonitorList{}
 	for _, envKeyMonitor := range envKeyMonitorList.Items {
-		for _, key := range envKeyMonitor.Spec.Keys {
-			allKeys = append(allKeys, key)
+		if rules.Bypasses(&envKeyMonitor, requester) {
+			bypassing.Items = append(bypassing.Items, envKeyMonitor)
+		} else {
+			enforced.Items = append(enforced.Items, envKeyMonitor)
+		}
+	}
+
+	violations := rules.Find(enforced, configmap)
+	for _, violation := range rules.Find(bypassing, configmap) {
+		if !slices.ContainsFunc(violations, func(other rules.Violation) bool {
+			return other.Field == violation.Field && other.Key == violation.Key && other.Kind == violation.Kind &&
+				other.Rule.Name == violation.Rule.Name && other.Rule.Match == violation.Rule.Match
+		}) {
+			violations = append(violations, violation)
 		}
 	}
-	return &allKeys
+	return violations
 }
 
-// Check if EnvKeyMonitor CRD in current namespace contain a key
-func checkConfigmapKeys(forbiddenKeysList *[]string, configmap *corev1.ConfigMap) error {
+// Check if a violation is enforced. Every violation is enforced on creation, on update only the
+// keys selected by .spec.enforceOn are enforced
+func isEnforced(violation rules.Violation, oldConfigmap, configmap *corev1.ConfigMap, reducesViolations bool) bool {
 
-	for _, forbiddenKey := range *forbiddenKeysList {
-		if _, keyExists := configmap.Data[forbiddenKey]; keyExists {
-			return fmt.Errorf(
-				"Configmap contains forbidden key and is therefore invalid. "+
-					"Forbidden key is '%s'",
-				forbiddenKey,
-			)
+	if oldConfigmap == nil {
+		return true
+	}
+	if violation.Monitor.Spec.EnforceOn == configv2.EnforceOnAllKeys {
+		return !reducesViolations
+	}
+	return rules.KeyChanged(oldConfigmap, configmap, violation)
+}
+
+// Emit an event on the EnvKeyMonitor whose rule was violated, unless the request is a dry run.
+// The event names the requesting user if ctx holds the admission request
+func (v *ConfigMapCustomValidator) recordViolation(ctx context.Context, violation rules.Violation, eventType, reason, message string) {
+
+	if isDryRun(ctx) {
+		return
+	}
+	subject := fmt.Sprintf("ConfigMap %s/%s", violation.Namespace, violation.ConfigMap)
+	if requester := requesterFromContext(ctx); requester != nil {
+		subject = fmt.Sprintf("%s by %s", subject, describeRequester(requester))
+	}
+	v.Recorder.Eventf(violation.Monitor, eventType, reason, "%s: %s", subject, message)
+}
+
+// Write an admission decision to the audit log. The decision follows from the returned warnings
+// and error, request metadata is taken from the admission request in ctx if present
+func (v *ConfigMapCustomValidator) auditDecision(
+	ctx context.Context,
+	start time.Time,
+	configmap *corev1.ConfigMap,
+	violations []rules.Violation,
+	warnings admission.Warnings,
+	err error,
+) {
+
+	if v.AuditLog == nil {
+		return
+	}
+
+	record := audit.Record{
+		Time:                start.UTC(),
+		Namespace:           configmap.GetNamespace(),
+		ConfigMap:           configmap.GetName(),
+		Groups:              []string{},
+		Monitors:            []string{},
+		Rules:               []string{},
+		Keys:                []string{},
+		Decision:            audit.DecisionAllowed,
+		LatencyMilliseconds: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if req, reqErr := admission.RequestFromContext(ctx); reqErr == nil {
+		record.UID = string(req.UID)
+		record.Operation = string(req.Operation)
+		record.DryRun = isDryRun(ctx)
+	}
+	if requester := requesterFromContext(ctx); requester != nil {
+		record.User = requester.Username
+		record.Groups = requester.Groups
+		record.FieldManager = requester.FieldManager
+	}
+	for _, violation := range violations {
+		record.Monitors = append(record.Monitors, violation.Monitor.GetName())
+		if violation.Kind == "" {
+			record.Rules = append(record.Rules, violation.Rule.Name)
 		}
+		record.Keys = append(record.Keys, violation.Key)
+	}
+	for _, names := range []*[]string{&record.Monitors, &record.Rules, &record.Keys} {
+		slices.Sort(*names)
+		*names = slices.Compact(*names)
 	}
-	return nil
+	if err != nil {
+		record.Decision = audit.DecisionDenied
+	} else if len(warnings) > 0 {
+		record.Decision = audit.DecisionWarned
+	}
+
+	v.AuditLog.Log(record)
+}
+
+// Count a violation in the metrics and queue it for the NotificationTarget objects of its
+// EnvKeyMonitor, unless the request is a dry run
+func (v *ConfigMapCustomValidator) reportViolation(ctx context.Context, violation rules.Violation, action string) {
+
+	if isDryRun(ctx) {
+		return
+	}
+	v.Notifier.Notify(violation, action)
+	metrics.Violations.WithLabelValues(
+		violation.Namespace,
+		violation.Monitor.GetName(),
+		string(rules.ModeOf(violation.Monitor)),
+		string(violation.Rule.Severity),
+		action,
+	).Inc()
+}
+
+// Get the user and field manager of the admission request, nil if ctx holds no admission request
+func requesterFromContext(ctx context.Context) *configv2.Requester {
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil
+	}
+	requester := &configv2.Requester{
+		Username: req.UserInfo.Username,
+		Groups:   req.UserInfo.Groups,
+	}
+
+	// Create and update options both hold the field manager, delete options have none
+	var options metav1.UpdateOptions
+	if len(req.Options.Raw) > 0 && json.Unmarshal(req.Options.Raw, &options) == nil {
+		requester.FieldManager = options.FieldManager
+	}
+	return requester
+}
+
+// Describe a requester in events, e.g. "user jane in groups [dev] with field manager kubectl"
+func describeRequester(requester *configv2.Requester) string {
+
+	description := fmt.Sprintf("user %s", requester.Username)
+	if len(requester.Groups) > 0 {
+		description = fmt.Sprintf("%s in groups [%s]", description, strings.Join(requester.Groups, ", "))
+	}
+	if requester.FieldManager != "" {
+		description = fmt.Sprintf("%s with field manager %s", description, requester.FieldManager)
+	}
+	return description
+}
+
+// Check if the admission request is a dry run
+func isDryRun(ctx context.Context) bool {
+
+	req, err := admission.RequestFromContext(ctx)
+	return err == nil && req.DryRun != nil && *req.DryRun
 }
@@ -17,11 +17,35 @@ limitations under the License.
 package v1
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
-	// TODO (user): Add any additional imports if needed
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/audit"
+	"github.com/Nivesh00/config-keys-operator.git/internal/controller"
+	"github.com/Nivesh00/config-keys-operator.git/internal/metrics"
 )
 
 var _ = Describe("ConfigMap Webhook", func() {
@@ -29,43 +53,600 @@ var _ = Describe("ConfigMap Webhook", func() {
 		obj       *corev1.ConfigMap
 		oldObj    *corev1.ConfigMap
 		validator ConfigMapCustomValidator
+		recorder  *record.FakeRecorder
 	)
 
 	BeforeEach(func() {
-		obj = &corev1.ConfigMap{}
-		oldObj = &corev1.ConfigMap{}
-		validator = ConfigMapCustomValidator{}
+		monitor := &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules: []configv2.KeyRule{
+					{Name: "API_KEY", Match: configv2.MatchExact},
+					{Name: "AWS_", Match: configv2.MatchPrefix},
+					{Name: "^.*_TOKEN$", Match: configv2.MatchRegex},
+				},
+				Policy: configv2.PolicyStrict,
+			},
+		}
+		permissiveMonitor := &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "permissive-monitor", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules: []configv2.KeyRule{
+					{Name: "DEBUG_", Match: configv2.MatchPrefix},
+					{Name: "PASSWORD", Match: configv2.MatchSuffix, Message: "{{ .Key }} belongs in Vault"},
+				},
+				Policy:  configv2.PolicyPermissive,
+				Message: "{{ .Key }} in {{ .Namespace }}/{{ .ConfigMap }} is monitored by {{ .Monitor }}",
+				DocsURL: "https://wiki.example.com/{{ .Key }}",
+			},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(monitor, permissiveMonitor).
+			WithStatusSubresource(monitor, permissiveMonitor).
+			Build()
+		recorder = record.NewFakeRecorder(10)
+
+		obj = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "configmap", Namespace: "default"},
+		}
+		oldObj = obj.DeepCopy()
+		validator = ConfigMapCustomValidator{Client: fakeClient, Recorder: recorder, StatusRecorder: NewStatusRecorder(fakeClient)}
 		Expect(validator).NotTo(BeNil(), "Expected validator to be initialized")
 		Expect(oldObj).NotTo(BeNil(), "Expected oldObj to be initialized")
 		Expect(obj).NotTo(BeNil(), "Expected obj to be initialized")
-		// TODO (user): Add any setup logic common to all tests
 	})
 
-	AfterEach(func() {
-		// TODO (user): Add any teardown logic common to all tests
+	Context("When creating or updating ConfigMap under Validating Webhook", func() {
+		It("Should deny creation if a key is matched exactly", func() {
+			obj.Data = map[string]string{"API_KEY": "value"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny creation if a key is matched by prefix", func() {
+			obj.Data = map[string]string{"AWS_SECRET_ACCESS_KEY": "value"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny creation if a key is matched by regular expression", func() {
+			obj.Data = map[string]string{"GITHUB_TOKEN": "value"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should report every forbidden key in a single denial", func() {
+			obj.Data = map[string]string{"API_KEY": "value", "AWS_REGION": "value", "GITHUB_TOKEN": "value"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+			var statusErr *apierrors.StatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			fields := []string{}
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+			}
+			Expect(fields).To(ConsistOf("data[API_KEY]", "data[AWS_REGION]", "data[GITHUB_TOKEN]"))
+		})
+
+		It("Should deny creation if a key is matched by a referenced EnvKeySet", func() {
+			Expect(validator.Create(ctx, &configv2.EnvKeySet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cloud"},
+				Spec: configv2.EnvKeySetSpec{Rules: []configv2.KeyRule{
+					{Name: "GCP_", Match: configv2.MatchPrefix},
+				}},
+			})).To(Succeed())
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.KeySetRefs = []configv2.KeySetReference{{Name: "cloud"}}
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			obj.Data = map[string]string{"GCP_CREDENTIALS": "value"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("data[GCP_CREDENTIALS]")))
+		})
+
+		It("Should deny creation if a key is matched by a preset", func() {
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.Presets = []configv2.Preset{configv2.PresetDatabase}
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			obj.Data = map[string]string{"POSTGRES_PASSWORD": "value"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("data[POSTGRES_PASSWORD]")))
+		})
+
+		It("Should deny creation if a value is matched by a rule of the rule source", func() {
+			Expect(validator.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "appsec-rules", Namespace: "default"},
+				Data: map[string]string{"gitleaks.toml": `
+[[rules]]
+id = "github-pat"
+regex = '''ghp_[0-9a-zA-Z]{36}'''
+`},
+			})).To(Succeed())
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.RuleSource = &configv2.RuleSource{
+				ConfigMapKeyRef: corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "appsec-rules"},
+					Key:                  "gitleaks.toml",
+				},
+				Format: configv2.RuleSourceGitleaks,
+			}
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			obj.Data = map[string]string{"GIT_CREDENTIALS": "ghp_" + strings.Repeat("a", 36)}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("detected by gitleaks rule github-pat")))
+
+			obj.Data = map[string]string{"GIT_CREDENTIALS": "none"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit creation if no key is monitored", func() {
+			obj.Data = map[string]string{"LOG_LEVEL": "debug", "MY_API_KEY": "value"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should admit creation with rendered warnings if the policy is PERMISSIVE", func() {
+			obj.Data = map[string]string{"DEBUG_FLAGS": "all", "DB_PASSWORD": "value"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"DB_PASSWORD belongs in Vault. See https://wiki.example.com/DB_PASSWORD",
+				"DEBUG_FLAGS in default/configmap is monitored by permissive-monitor. "+
+					"See https://wiki.example.com/DEBUG_FLAGS",
+			))
+			Expect(recorder.Events).To(HaveLen(2))
+		})
+
+		It("Should record an event on the EnvKeyMonitor if creation is denied", func() {
+			obj.Data = map[string]string{"API_KEY": "value"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("API_KEY")))
+			Expect(recorder.Events).To(Receive(ContainSubstring("ForbiddenKeyDenied")))
+		})
+
+		It("Should validate updates correctly", func() {
+			oldObj.Data = map[string]string{"LOG_LEVEL": "debug"}
+			obj.Data = map[string]string{"LOG_LEVEL": "info"}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should only enforce added or modified keys on update", func() {
+			oldObj.Data = map[string]string{"API_KEY": "value", "LOG_LEVEL": "debug"}
+			obj.Data = map[string]string{"API_KEY": "value", "LOG_LEVEL": "info"}
+			warnings, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+
+			By("modifying the value of the forbidden key")
+			obj.Data["API_KEY"] = "other"
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+
+			By("adding a forbidden key to binaryData")
+			obj.Data["API_KEY"] = "value"
+			obj.BinaryData = map[string][]byte{"AWS_SECRET_ACCESS_KEY": []byte("value")}
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("binaryData[AWS_SECRET_ACCESS_KEY]")))
+		})
+
+		It("Should enforce all keys on update unless violations are reduced", func() {
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.EnforceOn = configv2.EnforceOnAllKeys
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			oldObj.Data = map[string]string{"API_KEY": "value", "GITHUB_TOKEN": "value", "LOG_LEVEL": "debug"}
+			obj.Data = map[string]string{"API_KEY": "value", "GITHUB_TOKEN": "value", "LOG_LEVEL": "info"}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().To(HaveOccurred())
+
+			By("removing one of the forbidden keys")
+			delete(obj.Data, "GITHUB_TOKEN")
+			warnings, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("Should warn with a countdown until scheduled enforcement starts", func() {
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.EnforceAfter = &metav1.Time{Time: time.Now().Add(72 * time.Hour)}
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			obj.Data = map[string]string{"API_KEY": "value"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("Enforcement starts at")))
+
+			By("denying once enforcement started")
+			monitor.Spec.EnforceAfter = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should apply the action of the severity policy", func() {
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "permissive-monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.Rules[0].Severity = configv2.SeverityCritical
+			monitor.Spec.Rules[1].Severity = configv2.SeverityLow
+			monitor.Spec.SeverityPolicy = []configv2.SeverityPolicy{
+				{Severity: configv2.SeverityCritical, Action: configv2.SeverityActionDeny},
+				{Severity: configv2.SeverityLow, Action: configv2.SeverityActionAudit},
+			}
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			obj.Data = map[string]string{"DEBUG_TOKEN_X": "value", "DB_PASSWORD": "value"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("data[DEBUG_TOKEN_X]")))
+			Expect(err).NotTo(MatchError(ContainSubstring("DB_PASSWORD")))
+			Expect(warnings).To(BeEmpty())
+			Expect(testutil.ToFloat64(metrics.Violations.WithLabelValues(
+				"default", "permissive-monitor", string(configv2.ModeEnforce), string(configv2.SeverityCritical), metrics.ActionDenied,
+			))).To(BeNumerically(">=", 1))
+		})
+
+		It("Should admit and record would-be denials in Shadow mode", func() {
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.Mode = configv2.ModeShadow
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			By("not recording configmaps denied by another object")
+			obj.Data = map[string]string{"API_KEY": "value", "LEGACY_SECRET": "value"}
+			Expect(validator.Create(ctx, &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "strict-monitor", Namespace: "default"},
+				Spec: configv2.EnvKeyMonitorSpec{
+					Rules:  []configv2.KeyRule{{Name: "LEGACY_SECRET"}},
+					Policy: configv2.PolicyStrict,
+				},
+			})).To(Succeed())
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("data[LEGACY_SECRET]")))
+			Expect(validator.StatusRecorder.queue).To(BeEmpty())
+			for len(recorder.Events) > 0 {
+				<-recorder.Events
+			}
+
+			By("recording admitted configmaps in the background")
+			obj.Data = map[string]string{"API_KEY": "value", "AWS_REGION": "eu-west-1"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+			Expect(recorder.Events).To(HaveLen(2))
+			Expect(<-recorder.Events).To(ContainSubstring("ForbiddenKeyShadowDenied"))
+			Expect(testutil.ToFloat64(metrics.Violations.WithLabelValues(
+				"default", "monitor", string(configv2.ModeShadow), "", metrics.ActionShadowDenied,
+			))).To(BeNumerically(">=", 2))
+			Expect(validator.StatusRecorder.queue).To(HaveLen(1))
+
+			runCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(validator.StatusRecorder.Start(runCtx)).To(Succeed())
+			}()
+			Eventually(func(g Gomega) {
+				g.Expect(validator.Get(ctx, client.ObjectKeyFromObject(monitor), monitor)).To(Succeed())
+				g.Expect(monitor.Status.ShadowDenials).NotTo(BeNil())
+				g.Expect(monitor.Status.ShadowDenials.Count).To(Equal(int64(2)))
+				g.Expect(monitor.Status.ShadowDenials.ConfigMaps).To(Equal([]string{"configmap"}))
+			}).Should(Succeed())
+		})
+
+		It("Should deny every key not approved by an Allowlist object", func() {
+			Expect(validator.Create(ctx, &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "allowlist", Namespace: "default"},
+				Spec: configv2.EnvKeyMonitorSpec{
+					ListType: configv2.ListTypeAllowlist,
+					Rules: []configv2.KeyRule{
+						{Name: "APP_NAME"},
+						{Name: "LOG_", Match: configv2.MatchPrefix},
+					},
+					Policy: configv2.PolicyStrict,
+				},
+			})).To(Succeed())
+
+			obj.Data = map[string]string{"APP_NAME": "shop", "LOG_LEVEL": "debug"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Data["DATABASE_HOST"] = "db"
+			obj.BinaryData = map[string][]byte{"cert.pem": []byte("value")}
+			_, err := validator.ValidateCreate(ctx, obj)
+			var statusErr *apierrors.StatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			fields := []string{}
+			for _, cause := range statusErr.ErrStatus.Details.Causes {
+				fields = append(fields, cause.Field)
+				Expect(cause.Message).To(ContainSubstring("not approved"))
+			}
+			Expect(fields).To(ConsistOf("data[DATABASE_HOST]", "binaryData[cert.pem]"))
+		})
+
+		It("Should deny or warn about keys not following the key name policy", func() {
+			Expect(validator.Create(ctx, &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "naming", Namespace: "default"},
+				Spec: configv2.EnvKeyMonitorSpec{
+					KeyNamePolicy: &configv2.KeyNamePolicy{Pattern: "^[A-Z][A-Z0-9_]*$", MaxLength: ptr.To[int32](20)},
+					Policy:        configv2.PolicyStrict,
+				},
+			})).To(Succeed())
+
+			obj.Data = map[string]string{"LOG_LEVEL": "debug", "log-format": "json"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("data[log-format]")))
+			Expect(err).To(MatchError(ContainSubstring("does not match '^[A-Z][A-Z0-9_]*$'")))
+			Expect(err).NotTo(MatchError(ContainSubstring("LOG_LEVEL")))
+
+			By("warning if the policy is PERMISSIVE")
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "naming", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.Policy = configv2.PolicyPermissive
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("Key 'log-format' is invalid")))
+		})
+
+		It("Should deny or warn about selected configmaps missing a required key", func() {
+			Expect(validator.Create(ctx, &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "required", Namespace: "default"},
+				Spec: configv2.EnvKeyMonitorSpec{
+					RequiredKeys: []configv2.RequiredKeys{{
+						ConfigMapSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+						Keys:              []string{"LOG_LEVEL", "SERVICE_NAME"},
+					}},
+					Policy: configv2.PolicyStrict,
+				},
+			})).To(Succeed())
+
+			obj.Data = map[string]string{"LOG_LEVEL": "debug"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+
+			obj.Labels = map[string]string{"app": "api"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("data[SERVICE_NAME]: Required value")))
+			Expect(err).NotTo(MatchError(ContainSubstring("LOG_LEVEL")))
+
+			By("only enforcing keys removed by an update")
+			oldObj.Labels = map[string]string{"app": "api"}
+			oldObj.Data = map[string]string{"LOG_LEVEL": "debug"}
+			obj.Data = map[string]string{"LOG_LEVEL": "info"}
+			warnings, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("Missing key is 'SERVICE_NAME'")))
+			obj.Data = map[string]string{}
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(MatchError(ContainSubstring("data[LOG_LEVEL]")))
+
+			By("warning if the policy is PERMISSIVE")
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "required", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.Policy = configv2.PolicyPermissive
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+			warnings, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(2))
+		})
+
+		It("Should admit without warnings and only record violations in Audit mode", func() {
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "permissive-monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.Mode = configv2.ModeAudit
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			obj.Data = map[string]string{"DEBUG_MODE": "true"}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(ContainSubstring("ForbiddenKeyAudited"))
+		})
+	})
+
+	Context("When the requester is listed in .spec.bypass", func() {
+		var requestCtx context.Context
+
+		BeforeEach(func() {
+			monitor := &configv2.EnvKeyMonitor{}
+			Expect(validator.Get(ctx, client.ObjectKey{Name: "monitor", Namespace: "default"}, monitor)).To(Succeed())
+			monitor.Spec.Bypass = &configv2.Bypass{
+				ServiceAccounts: []configv2.BypassServiceAccount{{Name: "ci"}},
+			}
+			Expect(validator.Update(ctx, monitor)).To(Succeed())
+
+			requestCtx = admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UID:       "uid-3",
+					Operation: admissionv1.Create,
+					UserInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:default:ci"},
+				},
+			})
+		})
+
+		It("Should admit with a warning, an event and a metric", func() {
+			obj.Data = map[string]string{"API_KEY": "value"}
+			before := testutil.ToFloat64(metrics.Violations.WithLabelValues(
+				"default", "monitor", string(configv2.ModeEnforce), "", metrics.ActionBypassed,
+			))
+
+			warnings, err := validator.ValidateCreate(requestCtx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(HaveSuffix("Denial bypassed for user system:serviceaccount:default:ci"))
+			Expect(<-recorder.Events).To(HavePrefix("Warning ForbiddenKeyBypassed "))
+			Expect(testutil.ToFloat64(metrics.Violations.WithLabelValues(
+				"default", "monitor", string(configv2.ModeEnforce), "", metrics.ActionBypassed,
+			))).To(Equal(before + 1))
+
+			By("denying other requesters")
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should still deny keys matched by an object without the bypass", func() {
+			other := &configv2.EnvKeyMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-monitor", Namespace: "default"},
+				Spec: configv2.EnvKeyMonitorSpec{
+					Rules:  []configv2.KeyRule{{Name: "API_KEY", Match: configv2.MatchExact}},
+					Policy: configv2.PolicyStrict,
+				},
+			}
+			Expect(validator.Create(ctx, other)).To(Succeed())
+
+			obj.Data = map[string]string{"API_KEY": "value"}
+			Expect(validator.ValidateCreate(requestCtx, obj)).Error().To(MatchError(ContainSubstring("data[API_KEY]")))
+		})
+	})
+
+	Context("When deleting ConfigMap under Validating Webhook", func() {
+		It("Should admit deletion and audit the resolved violations", func() {
+			obj.Data = map[string]string{"API_KEY": "value", "LOG_LEVEL": "debug"}
+			Expect(validator.ValidateDelete(ctx, obj)).Error().NotTo(HaveOccurred())
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(Equal(
+				"Normal ViolationResolvedByDelete ConfigMap default/configmap: forbidden key 'API_KEY' was removed by deleting the configmap",
+			))
+		})
 	})
 
-	Context("When creating or updating ConfigMap under Validating Webhook", func() {
-		// TODO (user): Add logic for validating webhooks
-		// Example:
-		// It("Should deny creation if a required field is missing", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = ""
-		//     Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
-		// })
-		//
-		// It("Should admit creation if all required fields are present", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = "valid_value"
-		//     Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
-		// })
-		//
-		// It("Should validate updates correctly", func() {
-		//     By("simulating a valid update scenario")
-		//     oldObj.SomeRequiredField = "updated_value"
-		//     obj.SomeRequiredField = "updated_value"
-		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
-		// })
+	Context("When auditing admission decisions", func() {
+		It("Should write every decision without key values to the audit log", func() {
+			var buffer bytes.Buffer
+			validator.AuditLog = audit.NewLogger(&buffer)
+			requestCtx := admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UID:       "uid-1",
+					Operation: admissionv1.Create,
+					UserInfo: authenticationv1.UserInfo{
+						Username: "jane",
+						Groups:   []string{"developers"},
+					},
+					Options: runtime.RawExtension{Raw: []byte(`{"fieldManager":"kubectl"}`)},
+				},
+			})
+
+			obj.Data = map[string]string{"API_KEY": "secret-value", "AWS_REGION": "secret-value"}
+			Expect(validator.ValidateCreate(requestCtx, obj)).Error().To(HaveOccurred())
+			obj.Data = map[string]string{"LOG_LEVEL": "debug"}
+			Expect(validator.ValidateCreate(requestCtx, obj)).Error().NotTo(HaveOccurred())
+
+			Expect(buffer.String()).NotTo(ContainSubstring("secret-value"))
+			lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+			Expect(lines).To(HaveLen(2))
+
+			var denied, allowed audit.Record
+			Expect(json.Unmarshal([]byte(lines[0]), &denied)).To(Succeed())
+			Expect(json.Unmarshal([]byte(lines[1]), &allowed)).To(Succeed())
+			Expect(denied.UID).To(Equal("uid-1"))
+			Expect(denied.User).To(Equal("jane"))
+			Expect(denied.Groups).To(Equal([]string{"developers"}))
+			Expect(denied.FieldManager).To(Equal("kubectl"))
+			Expect(denied.Operation).To(Equal("CREATE"))
+			Expect(denied.ConfigMap).To(Equal("configmap"))
+			Expect(denied.Monitors).To(Equal([]string{"monitor"}))
+			Expect(denied.Rules).To(Equal([]string{"API_KEY", "AWS_"}))
+			Expect(denied.Keys).To(Equal([]string{"API_KEY", "AWS_REGION"}))
+			Expect(denied.Decision).To(Equal(audit.DecisionDenied))
+			Expect(allowed.Decision).To(Equal(audit.DecisionAllowed))
+			Expect(allowed.Keys).To(BeEmpty())
+			Expect(denied.DryRun).To(BeFalse())
+		})
+
+		It("Should mark decisions on dry run requests in the audit log", func() {
+			var buffer bytes.Buffer
+			validator.AuditLog = audit.NewLogger(&buffer)
+			requestCtx := admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UID:       "uid-dry-run",
+					Operation: admissionv1.Create,
+					DryRun:    ptr.To(true),
+				},
+			})
+
+			obj.Data = map[string]string{"API_KEY": "value"}
+			Expect(validator.ValidateCreate(requestCtx, obj)).Error().To(HaveOccurred())
+
+			var record audit.Record
+			Expect(json.Unmarshal(buffer.Bytes(), &record)).To(Succeed())
+			Expect(record.UID).To(Equal("uid-dry-run"))
+			Expect(record.DryRun).To(BeTrue())
+			Expect(record.Decision).To(Equal(audit.DecisionDenied))
+		})
+
+		It("Should record the admitting user on violations and events", func() {
+			requestCtx := admission.NewContextWithRequest(ctx, admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UID:       "uid-2",
+					Operation: admissionv1.Create,
+					UserInfo: authenticationv1.UserInfo{
+						Username: "jane",
+						Groups:   []string{"developers"},
+					},
+					Options: runtime.RawExtension{Raw: []byte(`{"fieldManager":"kubectl"}`)},
+				},
+			})
+
+			obj.Data = map[string]string{"DEBUG_FLAGS": "all"}
+			Expect(validator.ValidateCreate(requestCtx, obj)).Error().NotTo(HaveOccurred())
+			Expect(<-recorder.Events).To(HavePrefix(
+				"Warning ForbiddenKeyAdmitted ConfigMap default/configmap by user jane in groups [developers] " +
+					"with field manager kubectl: ",
+			))
+
+			runCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				defer GinkgoRecover()
+				Expect(validator.StatusRecorder.Start(runCtx)).To(Succeed())
+			}()
+			requester := configv2.Requester{
+				Username:     "jane",
+				Groups:       []string{"developers"},
+				FieldManager: "kubectl",
+			}
+			key := client.ObjectKey{Namespace: "default", Name: "permissive-monitor"}
+			var permissiveMonitor configv2.EnvKeyMonitor
+
+			By("keeping the admission pending while the controller has not seen the configmap")
+			Eventually(func(g Gomega) {
+				g.Expect(validator.Get(ctx, key, &permissiveMonitor)).To(Succeed())
+				g.Expect(permissiveMonitor.Status.PendingAdmissions).To(HaveLen(1))
+			}).Should(Succeed())
+			Expect(permissiveMonitor.Status.PendingAdmissions[0].Key).To(Equal("DEBUG_FLAGS"))
+			Expect(permissiveMonitor.Status.PendingAdmissions[0].AdmittedBy).To(Equal(requester))
+			Expect(permissiveMonitor.Status.Violations).To(BeEmpty())
+
+			reconciler := &controller.EnvKeyMonitorReconciler{
+				Client:   validator.Client,
+				Scheme:   validator.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validator.Get(ctx, key, &permissiveMonitor)).To(Succeed())
+			Expect(permissiveMonitor.Status.Violations).To(BeEmpty())
+			Expect(permissiveMonitor.Status.PendingAdmissions).To(HaveLen(1))
+
+			By("recording the user once the controller opens the violation")
+			Expect(validator.Create(ctx, obj)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(validator.Get(ctx, key, &permissiveMonitor)).To(Succeed())
+			Expect(permissiveMonitor.Status.PendingAdmissions).To(BeEmpty())
+			Expect(permissiveMonitor.Status.Violations).To(HaveLen(1))
+			Expect(permissiveMonitor.Status.Violations[0].Key).To(Equal("DEBUG_FLAGS"))
+			Expect(permissiveMonitor.Status.Violations[0].State).To(Equal(configv2.ViolationOpen))
+			Expect(permissiveMonitor.Status.Violations[0].AdmittedBy).To(Equal(&requester))
+
+			By("recording later admissions on the open violation")
+			Expect(validator.ValidateUpdate(requestCtx, obj, obj)).Error().NotTo(HaveOccurred())
+			Eventually(func(g Gomega) {
+				g.Expect(validator.Get(ctx, key, &permissiveMonitor)).To(Succeed())
+				g.Expect(permissiveMonitor.Status.Violations[0].AdmittedBy).To(Equal(&requester))
+				g.Expect(permissiveMonitor.Status.PendingAdmissions).To(BeEmpty())
+			}).Should(Succeed())
+		})
 	})
 
 })
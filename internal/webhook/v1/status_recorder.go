@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+const (
+	// Number of configmaps listed in .status.shadowDenials of each EnvKeyMonitor
+	maxShadowDeniedConfigMaps = 10
+	// Number of admitted configmaps waiting to be recorded, further records are dropped
+	statusQueueSize = 1000
+	// Number of admissions kept in .status.pendingAdmissions of each EnvKeyMonitor
+	maxPendingAdmissions = 100
+)
+
+// Violations of a single admitted configmap, with the requester and time of the admission request
+type statusRecord struct {
+	shadowDenied []rules.Violation
+	admitted     []rules.Violation
+	requester    *configv2.Requester
+	time         metav1.Time
+}
+
+// StatusRecorder writes the shadow denials and admitting users of admitted configmaps to the status
+// of EnvKeyMonitor objects in the background, so admission does not wait for status updates.
+// It runs on every replica of the manager, as every replica serves admission requests
+type StatusRecorder struct {
+	client.Client
+
+	queue chan statusRecord
+}
+
+// NewStatusRecorder returns a StatusRecorder using the client to update EnvKeyMonitor objects
+func NewStatusRecorder(c client.Client) *StatusRecorder {
+	return &StatusRecorder{
+		Client: c,
+		queue:  make(chan statusRecord, statusQueueSize),
+	}
+}
+
+// Record queues the violations of an admitted configmap, unless the request is a dry run. It never
+// blocks, records are dropped if the queue is full. Calling Record on a nil StatusRecorder does nothing
+func (r *StatusRecorder) Record(ctx context.Context, shadowDenied, admitted []rules.Violation) {
+
+	if r == nil || (len(shadowDenied) == 0 && len(admitted) == 0) || isDryRun(ctx) {
+		return
+	}
+	select {
+	case r.queue <- statusRecord{
+		shadowDenied: shadowDenied,
+		admitted:     admitted,
+		requester:    requesterFromContext(ctx),
+		time:         metav1.Now(),
+	}:
+	default:
+		configmaplog.Info("Status queue is full, dropping shadow denials and admitting user")
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, every replica records the
+// configmaps admitted by its own webhook server
+func (r *StatusRecorder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable. It writes queued records one at a time
+func (r *StatusRecorder) Start(ctx context.Context) error {
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case record := <-r.queue:
+			r.recordShadowDenials(ctx, record)
+			r.recordAdmittedBy(ctx, record)
+		}
+	}
+}
+
+// Add the violations a Shadow EnvKeyMonitor would have denied to its status. Failures are only
+// logged, the configmap was admitted regardless
+func (r *StatusRecorder) recordShadowDenials(ctx context.Context, record statusRecord) {
+
+	violations := record.shadowDenied
+	if len(violations) == 0 {
+		return
+	}
+
+	// Group violations by object
+	counts := map[types.NamespacedName]int64{}
+	for _, violation := range violations {
+		counts[client.ObjectKeyFromObject(violation.Monitor)]++
+	}
+
+	configmap := violations[0].ConfigMap
+	now := record.time
+	for _, key := range slices.SortedFunc(maps.Keys(counts), func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	}) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			var envKeyMonitor configv2.EnvKeyMonitor
+			if err := r.Get(ctx, key, &envKeyMonitor); err != nil {
+				return err
+			}
+			shadowDenials := envKeyMonitor.Status.ShadowDenials
+			if shadowDenials == nil {
+				shadowDenials = &configv2.ShadowDenials{}
+			}
+			shadowDenials.Count += counts[key]
+			shadowDenials.LastDenialTime = &now
+			shadowDenials.ConfigMaps = append(slices.DeleteFunc(shadowDenials.ConfigMaps, func(name string) bool {
+				return name == configmap
+			}), configmap)
+			if len(shadowDenials.ConfigMaps) > maxShadowDeniedConfigMaps {
+				shadowDenials.ConfigMaps = shadowDenials.ConfigMaps[len(shadowDenials.ConfigMaps)-maxShadowDeniedConfigMaps:]
+			}
+			envKeyMonitor.Status.ShadowDenials = shadowDenials
+			return r.Status().Update(ctx, &envKeyMonitor)
+		})
+		if err != nil {
+			configmaplog.Error(err, "Cannot record shadow denials", "monitor", key.String())
+		}
+	}
+}
+
+// Record the requesting user on the violation records of admitted violations. Violations the
+// controller has not opened yet are added to .status.pendingAdmissions instead, the configmap may
+// not be in the cache of the controller yet or may still be rejected by another webhook, and the
+// controller records the user once it opens the violation. Nothing is recorded if the request held
+// no requester
+func (r *StatusRecorder) recordAdmittedBy(ctx context.Context, record statusRecord) {
+
+	violations, requester := record.admitted, record.requester
+	if len(violations) == 0 || requester == nil {
+		return
+	}
+
+	// Group violations by object
+	byMonitor := map[types.NamespacedName][]rules.Violation{}
+	for _, violation := range violations {
+		key := client.ObjectKeyFromObject(violation.Monitor)
+		byMonitor[key] = append(byMonitor[key], violation)
+	}
+
+	now := record.time
+	for _, key := range slices.SortedFunc(maps.Keys(byMonitor), func(a, b types.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	}) {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			var envKeyMonitor configv2.EnvKeyMonitor
+			if err := r.Get(ctx, key, &envKeyMonitor); err != nil {
+				return err
+			}
+			for _, violation := range byMonitor[key] {
+				index := slices.IndexFunc(envKeyMonitor.Status.Violations, func(existing configv2.ViolationRecord) bool {
+					return existing.ResolvedAt == nil &&
+						existing.ConfigMap == violation.ConfigMap &&
+						existing.Key == violation.Key &&
+						existing.Kind == violation.Kind &&
+						existing.Rule == violation.Rule.Name
+				})
+				if index >= 0 {
+					envKeyMonitor.Status.Violations[index].AdmittedBy = requester
+					continue
+				}
+				pending := configv2.PendingAdmission{
+					ConfigMap:  violation.ConfigMap,
+					Key:        violation.Key,
+					Rule:       violation.Rule.Name,
+					Kind:       violation.Kind,
+					AdmittedBy: *requester,
+					Time:       now,
+				}
+				envKeyMonitor.Status.PendingAdmissions = append(
+					slices.DeleteFunc(envKeyMonitor.Status.PendingAdmissions, func(existing configv2.PendingAdmission) bool {
+						return existing.ConfigMap == pending.ConfigMap &&
+							existing.Key == pending.Key &&
+							existing.Kind == pending.Kind &&
+							existing.Rule == pending.Rule
+					}),
+					pending,
+				)
+			}
+			if len(envKeyMonitor.Status.PendingAdmissions) > maxPendingAdmissions {
+				envKeyMonitor.Status.PendingAdmissions = envKeyMonitor.Status.PendingAdmissions[len(envKeyMonitor.Status.PendingAdmissions)-maxPendingAdmissions:]
+			}
+			return r.Status().Update(ctx, &envKeyMonitor)
+		})
+		if err != nil {
+			configmaplog.Error(err, "Cannot record admitting user", "monitor", key.String())
+		}
+	}
+}
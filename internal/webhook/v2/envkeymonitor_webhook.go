@@ -0,0 +1,606 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+	"github.com/Nivesh00/config-keys-operator.git/internal/keysets"
+	"github.com/Nivesh00/config-keys-operator.git/internal/rules"
+)
+
+// nolint:unused
+// log is for logging in this package.
+var envKeyMonitorLog = logf.Log.WithName("envkeymonitor-resource")
+
+// Number of non-compliant configmaps listed in the impact warning
+const maxImpactPreview = 10
+
+// SetupEnvKeyMonitorWebhookWithManager registers the webhook for EnvKeyMonitor in the manager.
+func SetupEnvKeyMonitorWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&configv2.EnvKeyMonitor{}).
+		WithValidator(&EnvKeyMonitorCustomValidator{
+			mgr.GetClient(),
+		}).
+		WithValidatorCustomPath("/envkeymonitor-validate").
+		WithDefaulter(&EnvKeyMonitorCustomDefaulter{
+			mgr.GetClient(),
+		}).
+		WithDefaulterCustomPath("/envkeymonitor-mutate").
+		Complete()
+}
+
+// TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+
+// +kubebuilder:webhook:path=/envkeymonitor-mutate,mutating=true,failurePolicy=fail,sideEffects=None,groups=config.core.nvsh-ram.io,resources=envkeymonitors,verbs=create;update,versions=v2,name=menvkeymonitor-v2.kb.io,admissionReviewVersions=v1
+
+// EnvKeyMonitorCustomDefaulter struct is responsible for setting default values on the custom resource of the
+// Kind EnvKeyMonitor when those are created or updated.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as it is used only for temporary operations and does not need to be deeply copied.
+type EnvKeyMonitorCustomDefaulter struct {
+	// TODO(user): Add more fields as needed for defaulting
+	client.Client
+}
+
+var _ webhook.CustomDefaulter = &EnvKeyMonitorCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind EnvKeyMonitor.
+func (d *EnvKeyMonitorCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	envKeyMonitor, ok := obj.(*configv2.EnvKeyMonitor)
+
+	if !ok {
+		return fmt.Errorf("expected an EnvKeyMonitor object but got %T", obj)
+	}
+	envKeyMonitorLog.Info("Defaulting for EnvKeyMonitor", "name", envKeyMonitor.GetName())
+
+	// TODO(user): fill in your defaulting logic.
+
+	// Remove duplicates in new object
+	envKeyMonitor.Spec.Rules = d.RemoveDuplicatesInObject(envKeyMonitor)
+
+	// Rules held by other EnvKeyMonitor objects in the namespace are kept, overlapping
+	// objects are evaluated as a union and reported in '.status.sharedKeys'
+
+	return nil
+}
+
+// Check new object only to remove duplicates found in .spec.rules[]. Rules are duplicates if they
+// have the same name, match and value regex, e.g. imported rules sharing the name '.*' are kept
+func (d *EnvKeyMonitorCustomDefaulter) RemoveDuplicatesInObject(envKeyMonitor *configv2.EnvKeyMonitor) []configv2.KeyRule {
+
+	newKeysList := map[configv2.KeyRule]struct{}{}
+	var newRulesList []configv2.KeyRule
+	for _, rule := range envKeyMonitor.Spec.Rules {
+		if _, ok := newKeysList[keysets.Identity(rule)]; ok {
+			envKeyMonitorLog.Info(
+				"Object contains duplicate in '.spec.rules[]'. Removing duplicated key...",
+				"name",
+				envKeyMonitor.GetName(),
+				"namespace",
+				envKeyMonitor.GetNamespace(),
+				"duplicate_key",
+				rule.Name,
+			)
+			continue
+		}
+		newKeysList[keysets.Identity(rule)] = struct{}{}
+		newRulesList = append(newRulesList, rule)
+	}
+	return newRulesList
+}
+
+// NOTE: If you want to customise the 'path', use the flags '--defaulting-path' or '--validation-path'.
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:webhook:path=/envkeymonitor-validate,mutating=false,failurePolicy=fail,sideEffects=None,groups=config.core.nvsh-ram.io,resources=envkeymonitors,verbs=create;update;delete,versions=v2,name=venvkeymonitor-v2.kb.io,admissionReviewVersions=v1
+
+// EnvKeyMonitorCustomValidator struct is responsible for validating the EnvKeyMonitor resource
+// when it is created, updated, or deleted.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+type EnvKeyMonitorCustomValidator struct {
+	// TODO(user): Add more fields as needed for validation
+	client.Client
+}
+
+var _ webhook.CustomValidator = &EnvKeyMonitorCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type EnvKeyMonitor.
+func (v *EnvKeyMonitorCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	envKeyMonitor, ok := obj.(*configv2.EnvKeyMonitor)
+	if !ok {
+		return nil, fmt.Errorf("expected a EnvKeyMonitor object but got %T", obj)
+	}
+	envKeyMonitorLog.Info("Validation for EnvKeyMonitor upon creation", "name", envKeyMonitor.GetName())
+
+	// TODO(user): fill in your validation logic upon object creation.
+
+	// Check that the object monitors keys, objects written through v1 are not checked by the schema of v2
+	if err := v.CheckRuleSources(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot create new object")
+		return nil, err
+	}
+	// Check for duplicates in object
+	if err := v.CheckDuplicateKeysInObject(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot create new object")
+		return nil, err
+	}
+	// Check that rule names can match configmap keys
+	if err := v.CheckKeySyntax(nil, envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot create new object")
+		return nil, err
+	}
+	// Check that rule patterns can be compiled
+	if err := v.CheckRulePatterns(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot create new object")
+		return nil, err
+	}
+	// Check that messages and docs URLs can be rendered
+	if err := v.CheckMessageTemplates(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot create new object")
+		return nil, err
+	}
+	// Check that maintenance windows end after they start
+	if err := v.CheckMaintenanceWindows(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot create new object")
+		return nil, err
+	}
+	// Check how many existing configmaps become non-compliant
+	warnings, err := v.CheckImpactOnConfigMaps(&ctx, nil, envKeyMonitor)
+	if err != nil {
+		envKeyMonitorLog.Error(err, "Cannot create new object")
+		return warnings, err
+	}
+
+	return warnings, nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type EnvKeyMonitor.
+func (v *EnvKeyMonitorCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	envKeyMonitor, ok := newObj.(*configv2.EnvKeyMonitor)
+	if !ok {
+		return nil, fmt.Errorf("expected a EnvKeyMonitor object for the newObj but got %T", newObj)
+	}
+	oldEnvKeyMonitor, ok := oldObj.(*configv2.EnvKeyMonitor)
+	if !ok {
+		return nil, fmt.Errorf("expected a EnvKeyMonitor object for the oldObj but got %T", oldObj)
+	}
+	envKeyMonitorLog.Info("Validation for EnvKeyMonitor upon update", "name", envKeyMonitor.GetName())
+
+	// TODO(user): fill in your validation logic upon object update.
+
+	// Check that the object monitors keys, objects written through v1 are not checked by the schema of v2
+	if err := v.CheckRuleSources(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot update object")
+		return nil, err
+	}
+	// Check for duplicates in object
+	if err := v.CheckDuplicateKeysInObject(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot update object")
+		return nil, err
+	}
+	// Check that rule names can match configmap keys
+	if err := v.CheckKeySyntax(oldEnvKeyMonitor, envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot update object")
+		return nil, err
+	}
+	// Check that rule patterns can be compiled
+	if err := v.CheckRulePatterns(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot update object")
+		return nil, err
+	}
+	// Check that messages and docs URLs can be rendered
+	if err := v.CheckMessageTemplates(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot update object")
+		return nil, err
+	}
+	// Check that maintenance windows end after they start
+	if err := v.CheckMaintenanceWindows(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot update object")
+		return nil, err
+	}
+	// Check how many existing configmaps become non-compliant
+	warnings, err := v.CheckImpactOnConfigMaps(&ctx, oldEnvKeyMonitor, envKeyMonitor)
+	if err != nil {
+		envKeyMonitorLog.Error(err, "Cannot update object")
+		return warnings, err
+	}
+
+	return warnings, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type EnvKeyMonitor.
+func (v *EnvKeyMonitorCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	envKeyMonitor, ok := obj.(*configv2.EnvKeyMonitor)
+	if !ok {
+		return nil, fmt.Errorf("expected a EnvKeyMonitor object but got %T", obj)
+	}
+	envKeyMonitorLog.Info("Validation for EnvKeyMonitor upon deletion", "name", envKeyMonitor.GetName())
+
+	// TODO(user): fill in your validation logic upon object deletion.
+
+	// Check if object is protected from deletion
+	if err := v.CheckDeletionProtection(envKeyMonitor); err != nil {
+		envKeyMonitorLog.Error(err, "Cannot delete object")
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Check if deleting the object is allowed by .spec.deletionProtection
+func (v *EnvKeyMonitorCustomValidator) CheckDeletionProtection(envKeyMonitor *configv2.EnvKeyMonitor) error {
+
+	if !envKeyMonitor.Spec.DeletionProtection {
+		return nil
+	}
+
+	envKeyMonitorLog.Info(
+		"Object is protected from deletion",
+		"name",
+		envKeyMonitor.GetName(),
+		"namespace",
+		envKeyMonitor.GetNamespace(),
+	)
+
+	return fmt.Errorf(
+		"EnvKeyMonitor object %s in namespace %s is protected from deletion, "+
+			"set '.spec.deletionProtection' to false before deleting it",
+		envKeyMonitor.GetName(),
+		envKeyMonitor.GetNamespace(),
+	)
+}
+
+// Check if the object has at least one of rules, keySetRefs, presets, ruleSource, keyNamePolicy
+// or requiredKeys
+func (v *EnvKeyMonitorCustomValidator) CheckRuleSources(envKeyMonitor *configv2.EnvKeyMonitor) error {
+
+	spec := envKeyMonitor.Spec
+	if len(spec.Rules) > 0 || len(spec.KeySetRefs) > 0 || len(spec.Presets) > 0 || spec.RuleSource != nil ||
+		spec.KeyNamePolicy != nil || len(spec.RequiredKeys) > 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"EnvKeyMonitor object %s in namespace %s monitors no keys, "+
+			"at least one of rules, keySetRefs, presets, ruleSource, keyNamePolicy or requiredKeys is required",
+		envKeyMonitor.GetName(),
+		envKeyMonitor.GetNamespace(),
+	)
+}
+
+// Check if there are duplicates in current object, rules with the same name, match and value regex
+func (v *EnvKeyMonitorCustomValidator) CheckDuplicateKeysInObject(envKeyMonitor *configv2.EnvKeyMonitor) error {
+
+	newKeysList := map[configv2.KeyRule]struct{}{}
+	for _, rule := range envKeyMonitor.Spec.Rules {
+		id := keysets.Identity(rule)
+		if _, ok := newKeysList[id]; ok {
+
+			envKeyMonitorLog.Info(
+				"Duplicate keys found in object during validation",
+				"name",
+				envKeyMonitor.GetName(),
+				"namespace",
+				envKeyMonitor.GetNamespace(),
+				"duplicate_key",
+				rule.Name,
+			)
+
+			return fmt.Errorf(
+				"Duplicate keys found in EnvKeyMonitor object during validation. "+
+					"Key %s with match %s and value regex '%s' is listed more than once in EnvKeyMonitor object %s",
+				id.Name,
+				id.Match,
+				id.ValueRegex,
+				envKeyMonitor.GetName(),
+			)
+		}
+		newKeysList[id] = struct{}{}
+	}
+
+	return nil
+}
+
+// Check if the name of every rule not matching by regular expression can match a configmap key,
+// e.g. a name with a trailing space never does, and if every required key is a valid configmap key
+// with a valid selector. Every invalid name is reported at its index. On updates only rules, keys
+// and selectors not in the old object are checked, so objects admitted before a check was added
+// can still be updated, e.g. to remove a finalizer
+func (v *EnvKeyMonitorCustomValidator) CheckKeySyntax(
+	oldEnvKeyMonitor *configv2.EnvKeyMonitor,
+	envKeyMonitor *configv2.EnvKeyMonitor,
+) error {
+
+	oldRules := map[configv2.KeyRule]struct{}{}
+	oldKeys := map[string]struct{}{}
+	var oldSelectors []metav1.LabelSelector
+	if oldEnvKeyMonitor != nil {
+		for _, rule := range oldEnvKeyMonitor.Spec.Rules {
+			oldRules[keysets.Identity(rule)] = struct{}{}
+		}
+		for _, requiredKeys := range oldEnvKeyMonitor.Spec.RequiredKeys {
+			oldSelectors = append(oldSelectors, requiredKeys.ConfigMapSelector)
+			for _, key := range requiredKeys.Keys {
+				oldKeys[key] = struct{}{}
+			}
+		}
+	}
+
+	var errs field.ErrorList
+	rulesPath := field.NewPath("spec", "rules")
+	for i, rule := range envKeyMonitor.Spec.Rules {
+		if _, ok := oldRules[keysets.Identity(rule)]; ok {
+			continue
+		}
+		for _, msg := range rules.SyntaxErrors(rule) {
+			errs = append(errs, field.Invalid(rulesPath.Index(i).Child("name"), rule.Name, msg))
+		}
+	}
+	requiredKeysPath := field.NewPath("spec", "requiredKeys")
+	for i, requiredKeys := range envKeyMonitor.Spec.RequiredKeys {
+		if !slices.ContainsFunc(oldSelectors, func(selector metav1.LabelSelector) bool {
+			return apiequality.Semantic.DeepEqual(selector, requiredKeys.ConfigMapSelector)
+		}) {
+			errs = append(errs, metav1validation.ValidateLabelSelector(
+				&requiredKeys.ConfigMapSelector,
+				metav1validation.LabelSelectorValidationOptions{},
+				requiredKeysPath.Index(i).Child("configMapSelector"),
+			)...)
+		}
+		for j, key := range requiredKeys.Keys {
+			if _, ok := oldKeys[key]; ok {
+				continue
+			}
+			for _, msg := range validation.IsConfigMapKey(key) {
+				errs = append(errs, field.Invalid(requiredKeysPath.Index(i).Child("keys").Index(j), key, msg))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	envKeyMonitorLog.Info(
+		"Invalid rule names or required keys found in object during validation",
+		"name",
+		envKeyMonitor.GetName(),
+		"namespace",
+		envKeyMonitor.GetNamespace(),
+		"errors",
+		errs.ToAggregate().Error(),
+	)
+
+	return apierrors.NewInvalid(
+		configv2.GroupVersion.WithKind("EnvKeyMonitor").GroupKind(),
+		envKeyMonitor.GetName(),
+		errs,
+	)
+}
+
+// Check if all rules matching by regular expression, all value regular expressions and the pattern
+// of the key name policy contain a valid expression
+func (v *EnvKeyMonitorCustomValidator) CheckRulePatterns(envKeyMonitor *configv2.EnvKeyMonitor) error {
+
+	if keyNamePolicy := envKeyMonitor.Spec.KeyNamePolicy; keyNamePolicy != nil && keyNamePolicy.Pattern != "" {
+		if _, err := regexp.Compile(keyNamePolicy.Pattern); err != nil {
+			return fmt.Errorf(
+				"Key name policy pattern %s of EnvKeyMonitor object %s is not a valid regular expression: %v",
+				keyNamePolicy.Pattern,
+				envKeyMonitor.GetName(),
+				err,
+			)
+		}
+	}
+	for _, rule := range envKeyMonitor.Spec.Rules {
+		if rule.ValueRegex != "" {
+			if _, err := regexp.Compile(rule.ValueRegex); err != nil {
+				return fmt.Errorf(
+					"Value regex %s of rule %s of EnvKeyMonitor object %s is not a valid regular expression: %v",
+					rule.ValueRegex,
+					rule.Name,
+					envKeyMonitor.GetName(),
+					err,
+				)
+			}
+		}
+		if rule.Match != configv2.MatchRegex {
+			continue
+		}
+		if _, err := regexp.Compile(rule.Name); err != nil {
+			return fmt.Errorf(
+				"Rule %s of EnvKeyMonitor object %s is not a valid regular expression: %v",
+				rule.Name,
+				envKeyMonitor.GetName(),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// Check how many existing configmaps in the namespace are compliant with the old object and all other
+// objects in the namespace, but not with the new one. The result is reported as a warning, STRICT objects in
+// Enforce mode exceeding .spec.maxNonCompliantConfigMaps are rejected. oldEnvKeyMonitor is nil on creation
+func (v *EnvKeyMonitorCustomValidator) CheckImpactOnConfigMaps(
+	ctx *context.Context,
+	oldEnvKeyMonitor *configv2.EnvKeyMonitor,
+	envKeyMonitor *configv2.EnvKeyMonitor,
+) (admission.Warnings, error) {
+
+	// Get list of configmaps
+	var configmapList corev1.ConfigMapList
+	if err := v.List(*ctx, &configmapList, client.InNamespace(envKeyMonitor.GetNamespace())); err != nil {
+
+		envKeyMonitorLog.Info(
+			"Failed to check impact, cannot list configmaps in namespace",
+			"name",
+			envKeyMonitor.GetName(),
+			"namespace",
+			envKeyMonitor.GetNamespace(),
+		)
+
+		return nil, fmt.Errorf("Cannot list configmaps in namespace %s: %v", envKeyMonitor.GetNamespace(), err)
+	}
+
+	// Get list of EnvKeyMonitor, configmaps already non-compliant with another object are not impacted
+	var envKeyMonitorList configv2.EnvKeyMonitorList
+	if err := v.List(*ctx, &envKeyMonitorList, client.InNamespace(envKeyMonitor.GetNamespace())); err != nil {
+
+		envKeyMonitorLog.Info(
+			"Failed to check impact, cannot list EnvKeyMonitor CRDs in namespace",
+			"name",
+			envKeyMonitor.GetName(),
+			"namespace",
+			envKeyMonitor.GetNamespace(),
+		)
+
+		return nil, fmt.Errorf("Cannot list EnvKeyMonitor objects in namespace %s: %v", envKeyMonitor.GetNamespace(), err)
+	}
+
+	newList := &configv2.EnvKeyMonitorList{Items: []configv2.EnvKeyMonitor{*envKeyMonitor}}
+	oldList := &configv2.EnvKeyMonitorList{}
+	if oldEnvKeyMonitor != nil {
+		oldList.Items = append(oldList.Items, *oldEnvKeyMonitor)
+	}
+	for _, item := range envKeyMonitorList.Items {
+		if item.GetName() == envKeyMonitor.GetName() {
+			continue
+		}
+		oldList.Items = append(oldList.Items, item)
+	}
+
+	// Evaluate the rules of presets and referenced EnvKeySet objects as well
+	for _, list := range []*configv2.EnvKeyMonitorList{newList, oldList} {
+		if err := keysets.Resolve(*ctx, v.Client, list); err != nil {
+			return nil, fmt.Errorf("Cannot list EnvKeySet objects: %v", err)
+		}
+	}
+
+	var impacted []string
+	for i := range configmapList.Items {
+		configmap := &configmapList.Items[i]
+		if len(rules.Find(newList, configmap)) > 0 && len(rules.Find(oldList, configmap)) == 0 {
+			impacted = append(impacted, configmap.GetName())
+		}
+	}
+	if len(impacted) == 0 {
+		return nil, nil
+	}
+
+	envKeyMonitorLog.Info(
+		"Object makes existing configmaps non-compliant",
+		"name",
+		envKeyMonitor.GetName(),
+		"namespace",
+		envKeyMonitor.GetNamespace(),
+		"configmaps",
+		len(impacted),
+	)
+
+	preview := impacted
+	if len(preview) > maxImpactPreview {
+		preview = append(slices.Clone(preview[:maxImpactPreview]), "...")
+	}
+	warnings := admission.Warnings{fmt.Sprintf(
+		"This change makes %d ConfigMaps in namespace %s non-compliant: %s",
+		len(impacted),
+		envKeyMonitor.GetNamespace(),
+		strings.Join(preview, ", "),
+	)}
+
+	maxImpacted := envKeyMonitor.Spec.MaxNonCompliantConfigMaps
+	enforced := envKeyMonitor.Spec.Policy == configv2.PolicyStrict && rules.ModeOf(envKeyMonitor) == configv2.ModeEnforce
+	if enforced && maxImpacted != nil && len(impacted) > int(*maxImpacted) {
+		return warnings, fmt.Errorf(
+			"EnvKeyMonitor object %s would make %d ConfigMaps in namespace %s non-compliant, "+
+				"at most %d are allowed by '.spec.maxNonCompliantConfigMaps'",
+			envKeyMonitor.GetName(),
+			len(impacted),
+			envKeyMonitor.GetNamespace(),
+			*maxImpacted,
+		)
+	}
+
+	return warnings, nil
+}
+
+// Check if all maintenance windows end after they start
+func (v *EnvKeyMonitorCustomValidator) CheckMaintenanceWindows(envKeyMonitor *configv2.EnvKeyMonitor) error {
+
+	for i, window := range envKeyMonitor.Spec.MaintenanceWindows {
+		if window.End.After(window.Start.Time) {
+			continue
+		}
+		return fmt.Errorf(
+			"Field .spec.maintenanceWindows[%d] of EnvKeyMonitor object %s ends before it starts",
+			i,
+			envKeyMonitor.GetName(),
+		)
+	}
+
+	return nil
+}
+
+// Check if all messages and docs URLs are valid templates
+func (v *EnvKeyMonitorCustomValidator) CheckMessageTemplates(envKeyMonitor *configv2.EnvKeyMonitor) error {
+
+	templates := map[string]string{
+		".spec.message": envKeyMonitor.Spec.Message,
+		".spec.docsURL": envKeyMonitor.Spec.DocsURL,
+	}
+	for i, rule := range envKeyMonitor.Spec.Rules {
+		templates[fmt.Sprintf(".spec.rules[%d].message", i)] = rule.Message
+		templates[fmt.Sprintf(".spec.rules[%d].docsURL", i)] = rule.DocsURL
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(templates)) {
+		if err := rules.ValidateTemplate(templates[path]); err != nil {
+			return fmt.Errorf(
+				"Field %s of EnvKeyMonitor object %s is not a valid template: %v",
+				path,
+				envKeyMonitor.GetName(),
+				err,
+			)
+		}
+	}
+
+	return nil
+}
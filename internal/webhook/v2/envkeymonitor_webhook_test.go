@@ -0,0 +1,280 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv2 "github.com/Nivesh00/config-keys-operator.git/api/v2"
+)
+
+var _ = Describe("EnvKeyMonitor Webhook", func() {
+	var (
+		obj       *configv2.EnvKeyMonitor
+		oldObj    *configv2.EnvKeyMonitor
+		validator EnvKeyMonitorCustomValidator
+		defaulter EnvKeyMonitorCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		existing := &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+			Spec: configv2.EnvKeyMonitorSpec{
+				Rules: []configv2.KeyRule{{Name: "DB_PASSWORD", Match: configv2.MatchExact}},
+			},
+		}
+		configmaps := []client.Object{
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy-a", Namespace: "default"},
+				Data:       map[string]string{"LEGACY_SECRET": "value"},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy-b", Namespace: "default"},
+				Data:       map[string]string{"LEGACY_SECRET": "value", "LEGACY_TOKEN": "value"},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "legacy-c", Namespace: "other"},
+				Data:       map[string]string{"LEGACY_SECRET": "value"},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(existing).
+			WithObjects(configmaps...).
+			Build()
+
+		obj = &configv2.EnvKeyMonitor{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitor", Namespace: "default"},
+		}
+		oldObj = obj.DeepCopy()
+		validator = EnvKeyMonitorCustomValidator{fakeClient}
+		Expect(validator).NotTo(BeNil(), "Expected validator to be initialized")
+		defaulter = EnvKeyMonitorCustomDefaulter{fakeClient}
+		Expect(defaulter).NotTo(BeNil(), "Expected defaulter to be initialized")
+		Expect(oldObj).NotTo(BeNil(), "Expected oldObj to be initialized")
+		Expect(obj).NotTo(BeNil(), "Expected obj to be initialized")
+	})
+
+	Context("When creating EnvKeyMonitor under Defaulting Webhook", func() {
+		It("Should remove rules listed more than once in the object", func() {
+			obj.Spec.Rules = []configv2.KeyRule{
+				{Name: "API_KEY"},
+				{Name: "AWS_", Match: configv2.MatchPrefix},
+				{Name: "API_KEY"},
+			}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Rules).To(Equal([]configv2.KeyRule{
+				{Name: "API_KEY"},
+				{Name: "AWS_", Match: configv2.MatchPrefix},
+			}))
+		})
+
+		It("Should keep rules of the same name with another match or value regex", func() {
+			obj.Spec.Rules = []configv2.KeyRule{
+				{Name: ".*", Match: configv2.MatchRegex, ValueRegex: "^ghp_"},
+				{Name: ".*", Match: configv2.MatchRegex, ValueRegex: "^glpat-"},
+				{Name: "AWS_", Match: configv2.MatchPrefix},
+				{Name: "AWS_"},
+				{Name: "AWS_", Match: configv2.MatchExact},
+			}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Rules).To(Equal([]configv2.KeyRule{
+				{Name: ".*", Match: configv2.MatchRegex, ValueRegex: "^ghp_"},
+				{Name: ".*", Match: configv2.MatchRegex, ValueRegex: "^glpat-"},
+				{Name: "AWS_", Match: configv2.MatchPrefix},
+				{Name: "AWS_"},
+			}))
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should keep rules already held by another object in the namespace", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}, {Name: "DB_PASSWORD"}}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Rules).To(Equal([]configv2.KeyRule{{Name: "API_KEY"}, {Name: "DB_PASSWORD"}}))
+		})
+	})
+
+	Context("When creating or updating EnvKeyMonitor under Validating Webhook", func() {
+		It("Should deny creation if a rule is listed more than once", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}, {Name: "API_KEY"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should admit creation if another object in the namespace holds the rule", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "DB_PASSWORD"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny creation if a regular expression cannot be compiled", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "(TOKEN", Match: configv2.MatchRegex}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "TOKEN", ValueRegex: "(ghp_"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("Value regex")))
+
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "TOKEN"}}
+			obj.Spec.KeyNamePolicy = &configv2.KeyNamePolicy{Pattern: "^[A-Z"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(MatchError(ContainSubstring("Key name policy")))
+		})
+
+		It("Should deny creation if a rule name can never match a configmap key", func() {
+			obj.Spec.Rules = []configv2.KeyRule{
+				{Name: "API_KEY"},
+				{Name: "DB_PASSWORD "},
+				{Name: "AWS_*", Match: configv2.MatchPrefix},
+				{Name: "^AWS_.*$", Match: configv2.MatchRegex},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.rules[1].name")))
+			Expect(err).To(MatchError(ContainSubstring("spec.rules[2].name")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.rules[3].name")))
+		})
+
+		It("Should deny creation if required keys are invalid", func() {
+			obj.Spec.RequiredKeys = []configv2.RequiredKeys{{
+				ConfigMapSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn},
+				}},
+				Keys: []string{"LOG_LEVEL", "SERVICE NAME"},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.requiredKeys[0].configMapSelector")))
+			Expect(err).To(MatchError(ContainSubstring("spec.requiredKeys[0].keys[1]")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.requiredKeys[0].keys[0]")))
+
+			By("admitting valid required keys")
+			obj.Spec.RequiredKeys[0].ConfigMapSelector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}
+			obj.Spec.RequiredKeys[0].Keys = []string{"LOG_LEVEL", "SERVICE_NAME"}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should deny creation if a message cannot be rendered", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY", Message: "{{ .Key }"}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny creation if a docs URL uses an unknown variable", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}}
+			obj.Spec.DocsURL = "https://wiki.example.com/{{ .Team }}"
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should deny creation if a maintenance window ends before it starts", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}}
+			start := metav1.Now()
+			obj.Spec.MaintenanceWindows = []configv2.MaintenanceWindow{{Start: start, End: start}}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
+		})
+
+		It("Should admit creation if all rules are valid", func() {
+			obj.Spec.Rules = []configv2.KeyRule{
+				{Name: "API_KEY"},
+				{Name: "^.*_TOKEN$", Match: configv2.MatchRegex},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should warn about existing configmaps becoming non-compliant", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "LEGACY_", Match: configv2.MatchPrefix}}
+			warnings, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(
+				"This change makes 2 ConfigMaps in namespace default non-compliant: legacy-a, legacy-b",
+			))
+
+			By("only counting configmaps that were compliant before the update")
+			oldObj.Spec.Rules = []configv2.KeyRule{{Name: "LEGACY_SECRET"}}
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "LEGACY_SECRET"}, {Name: "LEGACY_TOKEN"}}
+			warnings, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+
+			By("not counting configmaps already non-compliant with another object")
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "DB_PASSWORD"}}
+			warnings, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny STRICT objects making too many configmaps non-compliant", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "LEGACY_SECRET"}}
+			obj.Spec.MaxNonCompliantConfigMaps = ptr.To[int32](1)
+			obj.Spec.Policy = configv2.PolicyStrict
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("would make 2 ConfigMaps")))
+
+			By("allowing the same change for PERMISSIVE objects")
+			obj.Spec.Policy = configv2.PolicyPermissive
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("allowing the same change for STRICT objects in Shadow mode")
+			obj.Spec.Policy = configv2.PolicyStrict
+			obj.Spec.Mode = configv2.ModeShadow
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny deletion of protected objects", func() {
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}}
+			obj.Spec.DeletionProtection = true
+			_, err := validator.ValidateDelete(ctx, obj)
+			Expect(err).To(MatchError(ContainSubstring("protected from deletion")))
+
+			By("allowing deletion once the protection is removed")
+			obj.Spec.DeletionProtection = false
+			Expect(validator.ValidateDelete(ctx, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should validate updates correctly", func() {
+			oldObj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}}
+			obj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}, {Name: "SECRET", Match: configv2.MatchContains}}
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("Should only check the syntax of rules and required keys changed by an update", func() {
+			oldObj.Spec.Rules = []configv2.KeyRule{{Name: "API_KEY"}, {Name: "DB_PASSWORD "}}
+			oldObj.Spec.RequiredKeys = []configv2.RequiredKeys{{Keys: []string{"SERVICE NAME"}}}
+			obj = oldObj.DeepCopy()
+			obj.Finalizers = []string{"example.com/cleanup"}
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("denying a rule added with an invalid name")
+			obj.Spec.Rules = append(obj.Spec.Rules, configv2.KeyRule{Name: "AWS_*", Match: configv2.MatchPrefix})
+			obj.Spec.RequiredKeys[0].Keys = append(obj.Spec.RequiredKeys[0].Keys, "LOG LEVEL")
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err).To(MatchError(ContainSubstring("spec.rules[2].name")))
+			Expect(err).To(MatchError(ContainSubstring("spec.requiredKeys[0].keys[1]")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.rules[1].name")))
+			Expect(err).NotTo(MatchError(ContainSubstring("spec.requiredKeys[0].keys[0]")))
+		})
+	})
+
+})